@@ -0,0 +1,116 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidApiKey = errors.New("invalid API key")
+
+// InvalidApiKeyError explains why an API key couldn't be parsed. It matches
+// ErrInvalidApiKey with errors.Is.
+type InvalidApiKeyError struct {
+	Reason string
+}
+
+func (e *InvalidApiKeyError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidApiKey, e.Reason)
+}
+
+func (e *InvalidApiKeyError) Is(target error) bool {
+	return target == ErrInvalidApiKey
+}
+
+// Key types as reported by Notify, for example in bulk job responses. The
+// type of a key isn't part of the key itself.
+type KeyType string
+
+const (
+	KeyTypeLive KeyType = "normal"
+	KeyTypeTeam KeyType = "team"
+	KeyTypeTest KeyType = "test"
+)
+
+// ParsedApiKey holds the parts of a combined API key of the form
+// {key name}-{service id}-{secret}.
+type ParsedApiKey struct {
+	Name      string
+	ServiceId string
+	Secret    string
+}
+
+// uuidLength is the length of the service ID and secret in an API key.
+const uuidLength = 36
+
+func ParseApiKey(apiKey string) (ParsedApiKey, error) {
+	if strings.ContainsAny(apiKey, " \t\r\n") {
+		return ParsedApiKey{}, &InvalidApiKeyError{Reason: "contains whitespace"}
+	}
+
+	// The name must be at least one character, followed by two separators
+	minLength := 1 + 1 + uuidLength + 1 + uuidLength
+
+	if len(apiKey) < minLength {
+		return ParsedApiKey{}, &InvalidApiKeyError{Reason: fmt.Sprintf("expected at least %d characters, got %d", minLength, len(apiKey))}
+	}
+
+	secretStart := len(apiKey) - uuidLength
+	serviceIdStart := secretStart - 1 - uuidLength
+
+	if apiKey[secretStart-1] != '-' || apiKey[serviceIdStart-1] != '-' {
+		return ParsedApiKey{}, &InvalidApiKeyError{Reason: "expected the key name, service ID and secret to be separated by -"}
+	}
+
+	key := ParsedApiKey{
+		Name:      apiKey[:serviceIdStart-1],
+		ServiceId: apiKey[serviceIdStart : secretStart-1],
+		Secret:    apiKey[secretStart:],
+	}
+
+	if !isUUID(key.ServiceId) {
+		return ParsedApiKey{}, &InvalidApiKeyError{Reason: "service ID is not a valid UUID"}
+	}
+
+	if !isUUID(key.Secret) {
+		return ParsedApiKey{}, &InvalidApiKeyError{Reason: "secret is not a valid UUID"}
+	}
+
+	return key, nil
+}
+
+// Redacted returns the key with all but the last 4 characters of the secret
+// hidden, suitable for logs. Secrets shorter than a UUID, such as in a key
+// that wasn't returned by ParseApiKey, are hidden entirely.
+func (k ParsedApiKey) Redacted() string {
+	if len(k.Secret) < uuidLength {
+		return fmt.Sprintf("%s-%s-%s", k.Name, k.ServiceId, strings.Repeat("*", len(k.Secret)))
+	}
+
+	return fmt.Sprintf("%s-%s-%s%s", k.Name, k.ServiceId, strings.Repeat("*", len(k.Secret)-4), k.Secret[len(k.Secret)-4:])
+}
+
+func (k ParsedApiKey) String() string {
+	return k.Redacted()
+}
+
+func isUUID(s string) bool {
+	if len(s) != uuidLength {
+		return false
+	}
+
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
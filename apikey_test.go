@@ -0,0 +1,102 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestParseApiKey(t *testing.T) {
+	t.Parallel()
+
+	got, err := ParseApiKey("my-test-key-26785a09-ab16-4eb0-8407-a37497a57506-3d844edf-8d35-48ac-975b-e847b4f122b0")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	want := ParsedApiKey{
+		Name:      "my-test-key",
+		ServiceId: "26785a09-ab16-4eb0-8407-a37497a57506",
+		Secret:    "3d844edf-8d35-48ac-975b-e847b4f122b0",
+	}
+
+	if got != want {
+		t.Errorf("ParseApiKey() = %+v, want %+v", got, want)
+	}
+
+	redacted := "my-test-key-26785a09-ab16-4eb0-8407-a37497a57506-********************************22b0"
+
+	if got.Redacted() != redacted {
+		t.Errorf("Redacted() = %s, want %s", got.Redacted(), redacted)
+	}
+}
+
+func TestParseApiKeyWithInvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		apiKey string
+		reason string
+	}{
+		{
+			name:   "too short",
+			apiKey: "bad_key",
+			reason: "expected at least 75 characters, got 7",
+		},
+		{
+			name:   "whitespace",
+			apiKey: " my_key-26785a09-ab16-4eb0-8407-a37497a57506-3d844edf-8d35-48ac-975b-e847b4f122b0",
+			reason: "contains whitespace",
+		},
+		{
+			name:   "missing separator",
+			apiKey: "my_key-26785a09-ab16-4eb0-8407-a37497a575063d844edf-8d35-48ac-975b-e847b4f122b0x",
+			reason: "expected the key name, service ID and secret to be separated by -",
+		},
+		{
+			name:   "invalid service ID",
+			apiKey: "my_key-26785a09-ab16-4eb0-8407-a37497a5750z-3d844edf-8d35-48ac-975b-e847b4f122b0",
+			reason: "service ID is not a valid UUID",
+		},
+		{
+			name:   "invalid secret",
+			apiKey: "my_key-26785a09-ab16-4eb0-8407-a37497a57506-3d844edf08d35048ac0975b0e847b4f122b0",
+			reason: "secret is not a valid UUID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseApiKey(tt.apiKey)
+
+			var keyErr *InvalidApiKeyError
+
+			if !errors.As(err, &keyErr) {
+				t.Fatalf("Expected *InvalidApiKeyError, got %v", err)
+			}
+
+			if keyErr.Reason != tt.reason {
+				t.Errorf("Expected reason to be %s, got %s", tt.reason, keyErr.Reason)
+			}
+
+			if !errors.Is(err, ErrInvalidApiKey) {
+				t.Errorf("Expected error to be ErrInvalidApiKey")
+			}
+		})
+	}
+}
+
+func TestRedactedShortSecret(t *testing.T) {
+	t.Parallel()
+
+	if got := (ParsedApiKey{}).String(); got != "--" {
+		t.Errorf("Expected a zero key to redact to --, got %s", got)
+	}
+
+	if got := (ParsedApiKey{Name: "test", ServiceId: "id", Secret: "abc"}).Redacted(); got != "test-id-***" {
+		t.Errorf("Expected a short secret to be hidden entirely, got %s", got)
+	}
+}
@@ -0,0 +1,128 @@
+package client_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+const combinedApiKey = "my_test_key-26785a09-ab16-4eb0-8407-a37497a57506-3d844edf-8d35-48ac-975b-e847b4f122b0"
+
+func TestNewJWTAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	a, err := NewJWTAuthenticator(combinedApiKey)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if a.ServiceId != "26785a09-ab16-4eb0-8407-a37497a57506" {
+		t.Errorf("Expected service ID to be 26785a09-ab16-4eb0-8407-a37497a57506, got %s", a.ServiceId)
+	}
+
+	if a.Secret != "3d844edf-8d35-48ac-975b-e847b4f122b0" {
+		t.Errorf("Expected secret to be 3d844edf-8d35-48ac-975b-e847b4f122b0, got %s", a.Secret)
+	}
+
+	_, err = NewJWTAuthenticator("bad_key")
+
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestJWTAuthenticatorToken(t *testing.T) {
+	t.Parallel()
+
+	a := JWTAuthenticator{
+		ServiceId: "26785a09-ab16-4eb0-8407-a37497a57506",
+		Secret:    "3d844edf-8d35-48ac-975b-e847b4f122b0",
+		ClockSkew: 5 * time.Second,
+		Now: func() time.Time {
+			return time.Unix(1700000000, 0)
+		},
+	}
+
+	token, err := a.Token()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	parts := strings.Split(token, ".")
+
+	if len(parts) != 3 {
+		t.Fatalf("Expected token to have 3 parts, got %d", len(parts))
+	}
+
+	// Verify the signature
+	mac := hmac.New(sha256.New, []byte(a.Secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+
+	if base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) != parts[2] {
+		t.Errorf("Expected token to be signed with the secret")
+	}
+
+	// Verify the header and claims
+	var header map[string]string
+	var claims map[string]any
+
+	decoded, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	json.Unmarshal(decoded, &header)
+
+	if header["alg"] != "HS256" || header["typ"] != "JWT" {
+		t.Errorf("Expected HS256 JWT header, got %v", header)
+	}
+
+	decoded, _ = base64.RawURLEncoding.DecodeString(parts[1])
+	json.Unmarshal(decoded, &claims)
+
+	if claims["iss"] != a.ServiceId {
+		t.Errorf("Expected iss to be %s, got %v", a.ServiceId, claims["iss"])
+	}
+
+	if claims["iat"] != float64(1700000000-5) {
+		t.Errorf("Expected iat to be %d, got %v", 1700000000-5, claims["iat"])
+	}
+}
+
+func TestClientAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	var authorization string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		ApiKey:     combinedApiKey,
+		HttpClient: http.Client{},
+		Hostname:   server.URL,
+	}
+
+	client.DoGetRequest("/v2/notifications")
+
+	if authorization != "ApiKey-v1 "+combinedApiKey {
+		t.Errorf("Expected ApiKey-v1 authorization by default, got %s", authorization)
+	}
+
+	client.Authenticator, _ = NewJWTAuthenticator(combinedApiKey)
+
+	client.DoGetRequest("/v2/notifications")
+
+	if !strings.HasPrefix(authorization, "Bearer ") || strings.Count(authorization, ".") != 2 {
+		t.Errorf("Expected bearer token authorization, got %s", authorization)
+	}
+}
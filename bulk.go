@@ -0,0 +1,355 @@
+package client
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidBulk is matched by errors for bulk sends that can't be sent to
+// the channel of their template, such as a missing recipient column.
+var ErrInvalidBulk = errors.New("invalid bulk send")
+
+// Bulk is a bulk send of an email, SMS or letter template. The first row of
+// Rows or Csv is the header, naming the recipient column and placeholders.
+type Bulk struct {
+	Name       string `json:"name"`
+	TemplateId string `json:"template_id"`
+
+	// Optional
+	Rows         [][]string `json:"rows,omitempty"`
+	ScheduledFor string     `json:"scheduled_for,omitempty"`
+	ReplyToId    string     `json:"reply_to_id,omitempty"` // An email reply-to or SMS sender ID
+	Csv          string     `json:"csv,omitempty"`
+
+	// Optional, detected from the recipient column when empty
+	Type TemplateType `json:"-"`
+
+	// Optional, rows streamed to Notify instead of Rows or Csv. The request
+	// is attempted once.
+	Source RowReader `json:"-"`
+}
+
+// BulkEmail is the request type SendBulkEmail was introduced with.
+type BulkEmail = Bulk
+
+// BulkEmailResponse is the response type SendBulkEmail was introduced with.
+type BulkEmailResponse = BulkResponse
+
+// recipientColumns are the columns a bulk send of each template type must
+// have, the first being the one its type is detected from.
+var recipientColumns = map[TemplateType][]string{
+	TemplateTypeEmail:  {"email address"},
+	TemplateTypeSms:    {"phone number"},
+	TemplateTypeLetter: {"address line 1", "address line 2"},
+}
+
+// postcodeColumns are the columns that can hold the postcode of a letter, as
+// it may be the last address line. Letters need at least one of them.
+var postcodeColumns = []string{
+	"postcode",
+	"address line 3",
+	"address line 4",
+	"address line 5",
+	"address line 6",
+	"address line 7",
+}
+
+type bulkDataResponseApiKey struct {
+	Id      string  `json:"id"`
+	KeyType KeyType `json:"key_type"`
+	Name    string  `json:"name"`
+}
+
+type bulkDataResponseCreatedBy struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bulkDataResponseService struct {
+	Name string `json:"name"`
+}
+
+type bulkDataResponse struct {
+	ApiKey             bulkDataResponseApiKey    `json:"api_key"`
+	Archived           bool                      `json:"archived"`
+	CreatedAt          string                    `json:"created_at"`
+	CreatedBy          bulkDataResponseCreatedBy `json:"created_by"`
+	Id                 string                    `json:"id"`
+	JobStatus          string                    `json:"job_status"`
+	NotificationCount  int                       `json:"notification_count"`
+	OriginalFileName   string                    `json:"original_file_name"`
+	ProcessingFinished string                    `json:"processing_finished"`
+	ProcessingStarted  string                    `json:"processing_started"`
+	SecheduledFor      string                    `json:"scheduled_for"`
+	SenderId           string                    `json:"sender_id"`
+	Service            string                    `json:"service"`
+	ServiceName        bulkDataResponseService   `json:"service_name"`
+	Template           string                    `json:"template"`
+	TemplateVersion    int                       `json:"template_version"`
+	UpdatedAt          string                    `json:"updated_at"`
+}
+
+type BulkResponse struct {
+	// Valid Response
+	Data bulkDataResponse `json:"data"`
+
+	// Error Response
+	StatusCode int             `json:"status_code"`
+	Errors     []ResponseError `json:"errors"`
+}
+
+// SendBulkEmail sends a bulk job. It is kept for compatibility and accepts
+// SMS and letter jobs the same way SendBulk does.
+func (c Client) SendBulkEmail(e BulkEmail) (BulkEmailResponse, error) {
+	return c.SendBulkContext(context.Background(), e)
+}
+
+func (c Client) SendBulkEmailContext(ctx context.Context, e BulkEmail) (BulkEmailResponse, error) {
+	return c.SendBulkContext(ctx, e)
+}
+
+// SendBulk sends a bulk job after checking its header has the recipient
+// columns of its template type. Rows count against the daily limit of that
+// channel.
+func (c Client) SendBulk(b Bulk) (BulkResponse, error) {
+	return c.SendBulkContext(context.Background(), b)
+}
+
+func (c Client) SendBulkContext(ctx context.Context, b Bulk) (BulkResponse, error) {
+	if b.Source != nil {
+		return c.sendBulkStream(ctx, b)
+	}
+
+	body, err := json.Marshal(b)
+
+	var response BulkResponse
+
+	if err != nil {
+		return response, fmt.Errorf("error marshalling body: %s", err)
+	}
+
+	t, rows, err := b.check()
+
+	if err != nil {
+		return response, err
+	}
+
+	if err := c.checkPinnedVersion(ctx, b.TemplateId); err != nil {
+		return response, err
+	}
+
+	ch := Channel(t)
+
+	release, err := c.reserveDailyLimit(ctx, ch, rows)
+
+	if err != nil {
+		return response, err
+	}
+
+	defer func() { release(response.StatusCode) }()
+
+	resp, err := c.doRequest(ctx, ch, "POST", "/v2/notifications/bulk", body)
+
+	if err != nil {
+		return response, fmt.Errorf("error calling bulk endpoint: %w", err)
+	}
+
+	raw, err := readResponse(resp, &response)
+
+	response.StatusCode = resp.StatusCode
+
+	if err != nil {
+		return response, fmt.Errorf("error decoding bulk response: %w", err)
+	}
+
+	if err := c.checkResponse(resp.StatusCode, raw); err != nil {
+		return response, err
+	}
+
+	return response, c.checkSentVersion(b.TemplateId, response.Data.TemplateVersion)
+}
+
+// table returns Rows, or the records of Csv, with the number of each row
+// counting the header as 1. Rows of Csv are numbered by the line they start
+// on, as blank lines aren't records.
+func (b Bulk) table() ([][]string, []int, error) {
+	if b.Rows != nil || b.Csv == "" {
+		numbers := make([]int, len(b.Rows))
+
+		for i := range numbers {
+			numbers[i] = i + 1
+		}
+
+		return b.Rows, numbers, nil
+	}
+
+	r := csv.NewReader(strings.NewReader(b.Csv))
+	r.FieldsPerRecord = -1
+
+	var (
+		table   [][]string
+		numbers []int
+	)
+
+	for {
+		record, err := r.Read()
+
+		if err == io.EOF {
+			return table, numbers, nil
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+		table = append(table, record)
+		numbers = append(numbers, line)
+	}
+}
+
+// check returns the template type of b and its number of rows that aren't
+// blank. A bulk send without any rows is left for Notify to reject, but a
+// header without column names is an error.
+func (b Bulk) check() (TemplateType, int, error) {
+	table, numbers, err := b.table()
+
+	if err != nil {
+		return "", 0, fmt.Errorf("error reading csv: %s", err)
+	}
+
+	if len(table) == 0 {
+		return b.Type, 0, nil
+	}
+
+	if isBlankRow(table[0]) {
+		return "", 0, fmt.Errorf("%w: the header has no column names", ErrInvalidBulk)
+	}
+
+	t, recipients, err := checkHeader(table[0], b.Type)
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	rows := 0
+
+	for n, row := range table[1:] {
+		// Notify skips blank rows, so they aren't checked or counted
+		if isBlankRow(row) {
+			continue
+		}
+
+		if err := checkRecipients(row, numbers[n+1], recipients); err != nil {
+			return "", 0, err
+		}
+
+		rows++
+	}
+
+	return t, rows, nil
+}
+
+// recipientColumn is a recipient column of a bulk send, which can be any of
+// several columns of the header, at least one of which must be filled.
+type recipientColumn struct {
+	name    string
+	indexes []int
+}
+
+// checkHeader returns the template type of a bulk send with header, detected
+// when t is empty, and the position of its recipient columns.
+func checkHeader(header []string, t TemplateType) (TemplateType, []recipientColumn, error) {
+	columns := map[string]int{}
+
+	for i, name := range header {
+		if _, ok := columns[normaliseKey(name)]; !ok {
+			columns[normaliseKey(name)] = i
+		}
+	}
+
+	if t == "" {
+		var err error
+
+		if t, err = detectTemplateType(columns); err != nil {
+			return "", nil, err
+		}
+	}
+
+	names, ok := recipientColumns[t]
+
+	if !ok {
+		return "", nil, fmt.Errorf("%w: invalid template type %q", ErrInvalidBulk, t)
+	}
+
+	var recipients []recipientColumn
+
+	for _, name := range names {
+		i, ok := columns[normaliseKey(name)]
+
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %s jobs need a %q column", ErrInvalidBulk, t, name)
+		}
+
+		recipients = append(recipients, recipientColumn{name, []int{i}})
+	}
+
+	if t == TemplateTypeLetter {
+		postcode := recipientColumn{name: "postcode"}
+
+		for _, name := range postcodeColumns {
+			if i, ok := columns[normaliseKey(name)]; ok {
+				postcode.indexes = append(postcode.indexes, i)
+			}
+		}
+
+		if len(postcode.indexes) == 0 {
+			return "", nil, fmt.Errorf("%w: letter jobs need a \"postcode\" or \"address line 3\" to \"address line 7\" column", ErrInvalidBulk)
+		}
+
+		recipients = append(recipients, postcode)
+	}
+
+	return t, recipients, nil
+}
+
+// checkRecipients returns an error when row, numbered number, has an empty
+// recipient column.
+func checkRecipients(row []string, number int, recipients []recipientColumn) error {
+	for _, col := range recipients {
+		if !slices.ContainsFunc(col.indexes, func(i int) bool {
+			return i < len(row) && strings.TrimSpace(row[i]) != ""
+		}) {
+			return fmt.Errorf("%w: row %d has no %s", ErrInvalidBulk, number, col.name)
+		}
+	}
+
+	return nil
+}
+
+// detectTemplateType returns the template type whose recipient column is
+// in columns.
+func detectTemplateType(columns map[string]int) (TemplateType, error) {
+	var found []TemplateType
+
+	for _, t := range []TemplateType{TemplateTypeEmail, TemplateTypeSms, TemplateTypeLetter} {
+		if _, ok := columns[normaliseKey(recipientColumns[t][0])]; ok {
+			found = append(found, t)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("%w: no email address, phone number or address line 1 column", ErrInvalidBulk)
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("%w: recipient columns for %s and %s, set Type to choose", ErrInvalidBulk, found[0], found[1])
+	}
+}
@@ -0,0 +1,312 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RowReader reads the rows of a bulk send one at a time, the first being the
+// header, returning io.EOF after the last. A *csv.Reader is a RowReader.
+type RowReader interface {
+	Read() ([]string, error)
+}
+
+// fieldPositioner is implemented by readers that know where the last row
+// they read starts, such as *csv.Reader.
+type fieldPositioner interface {
+	FieldPos(field int) (line, column int)
+}
+
+// rowNumber returns the number of row, the last one read from r, counting
+// the first row of the file as 1, or next when r doesn't know it.
+func rowNumber(r RowReader, row []string, next int) int {
+	if p, ok := r.(fieldPositioner); ok && len(row) > 0 {
+		line, _ := p.FieldPos(0)
+		return line
+	}
+
+	return next
+}
+
+// NewBulkFromCSV returns a bulk send streaming its rows from a CSV file. The
+// file may be UTF-8, with or without a byte order mark, or Windows-1252, and
+// separated by commas or, as Excel does in French, semicolons.
+func NewBulkFromCSV(name string, templateId string, r io.Reader) (Bulk, error) {
+	text, err := newTextReader(r)
+
+	if err != nil {
+		return Bulk{}, err
+	}
+
+	buffered := bufio.NewReader(text)
+
+	cr := csv.NewReader(buffered)
+	cr.FieldsPerRecord = -1
+	cr.Comma = sniffDelimiter(buffered)
+
+	return Bulk{Name: name, TemplateId: templateId, Source: cr}, nil
+}
+
+// NewBulkFromTSV returns a bulk send streaming its rows from a file of tab
+// separated values, in the encodings accepted by NewBulkFromCSV.
+func NewBulkFromTSV(name string, templateId string, r io.Reader) (Bulk, error) {
+	text, err := newTextReader(r)
+
+	if err != nil {
+		return Bulk{}, err
+	}
+
+	cr := csv.NewReader(text)
+	cr.FieldsPerRecord = -1
+	cr.Comma = '\t'
+	cr.LazyQuotes = true
+
+	return Bulk{Name: name, TemplateId: templateId, Source: cr}, nil
+}
+
+// sniffDelimiter returns ';' when the first line of r has more semicolons
+// than commas, and ',' otherwise.
+func sniffDelimiter(r *bufio.Reader) rune {
+	start, _ := r.Peek(r.Size())
+
+	if i := bytes.IndexByte(start, '\n'); i >= 0 {
+		start = start[:i]
+	}
+
+	if bytes.Count(start, []byte(";")) > bytes.Count(start, []byte(",")) {
+		return ';'
+	}
+
+	return ','
+}
+
+// headerColumns are the columns Notify gives a meaning to, which headers are
+// rewritten to when they match ignoring case, spaces, dashes and underscores.
+var headerColumns = []string{
+	"email address",
+	"phone number",
+	"address line 1",
+	"address line 2",
+	"address line 3",
+	"address line 4",
+	"address line 5",
+	"address line 6",
+	"address line 7",
+	"postcode",
+}
+
+// normaliseHeader trims the cells of a header and collapses their spaces,
+// rewrites recipient and address columns to the names Notify documents, and
+// drops empty cells at the end, as spreadsheets often have.
+func normaliseHeader(header []string) []string {
+	normalised := make([]string, len(header))
+
+	for i, cell := range header {
+		cell = strings.Join(strings.Fields(strings.TrimPrefix(cell, "\ufeff")), " ")
+
+		for _, column := range headerColumns {
+			if normaliseKey(cell) == normaliseKey(column) {
+				cell = column
+				break
+			}
+		}
+
+		normalised[i] = cell
+	}
+
+	for len(normalised) > 0 && normalised[len(normalised)-1] == "" {
+		normalised = normalised[:len(normalised)-1]
+	}
+
+	return normalised
+}
+
+type bulkStreamResult struct {
+	rows int
+	err  error
+}
+
+// sendBulkStream sends a bulk send reading its rows from b.Source as the
+// request body is written, so they are never all in memory. As the body
+// can't be replayed the request isn't retried, though the attempt is still
+// reported to c.Retry.OnAttempt.
+//
+// The number of rows isn't known until they are read, so the rest of today's
+// daily limit is reserved while the rows are sent, and what Notify didn't
+// accept is given back afterwards. Other sends on the channel are refused, or
+// wait with Defer, until then.
+func (c Client) sendBulkStream(ctx context.Context, b Bulk) (BulkResponse, error) {
+	var response BulkResponse
+
+	header, err := b.Source.Read()
+
+	if err == io.EOF {
+		return response, fmt.Errorf("%w: no header", ErrInvalidBulk)
+	}
+
+	if err != nil {
+		return response, fmt.Errorf("error reading header: %w", err)
+	}
+
+	header = normaliseHeader(header)
+
+	t, recipients, err := checkHeader(header, b.Type)
+
+	if err != nil {
+		return response, err
+	}
+
+	if err := c.checkPinnedVersion(ctx, b.TemplateId); err != nil {
+		return response, err
+	}
+
+	ch := Channel(t)
+	remaining, accepted := -1, 0
+
+	if c.LimitTracker != nil {
+		var reservedDay string
+
+		if reservedDay, remaining, err = c.LimitTracker.reserveRemaining(ctx, ch); err != nil {
+			return response, err
+		}
+
+		// Rows Notify didn't accept are given back once the send is done
+		if remaining >= 0 {
+			reserved := remaining
+
+			defer func() {
+				c.LimitTracker.release(ch, reservedDay, reserved-accepted)
+			}()
+		}
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan bulkStreamResult, 1)
+
+	go func() {
+		rows, err := writeBulkBody(pw, b, header, recipients, remaining)
+		pw.CloseWithError(err)
+		done <- bulkStreamResult{rows, err}
+	}()
+
+	resp, err := c.doStream(ctx, ch, "POST", "/v2/notifications/bulk", pr)
+
+	if c.Retry != nil && c.Retry.OnAttempt != nil {
+		a := RetryAttempt{Method: "POST", Endpoint: "/v2/notifications/bulk", Attempt: 1, Err: err}
+
+		if resp != nil {
+			a.StatusCode = resp.StatusCode
+		}
+
+		c.Retry.OnAttempt(a)
+	}
+
+	// Stops the writer if Notify responded before reading the whole body
+	pr.Close()
+	written := <-done
+
+	if written.err != nil && !errors.Is(written.err, io.ErrClosedPipe) {
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		return response, written.err
+	}
+
+	if err != nil {
+		return response, fmt.Errorf("error calling bulk endpoint: %w", err)
+	}
+
+	raw, err := readResponse(resp, &response)
+
+	response.StatusCode = resp.StatusCode
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		accepted = written.rows
+	}
+
+	if err != nil {
+		return response, fmt.Errorf("error decoding bulk response: %w", err)
+	}
+
+	if err := c.checkResponse(resp.StatusCode, raw); err != nil {
+		return response, err
+	}
+
+	return response, c.checkSentVersion(b.TemplateId, response.Data.TemplateVersion)
+}
+
+// writeBulkBody writes the JSON body of b with header and the rows left in
+// b.Source, returning the number of rows. It stops with an error at the first
+// row missing a recipient, past MaxBulkRows, or going over remaining when it
+// isn't -1.
+func writeBulkBody(w io.Writer, b Bulk, header []string, recipients []recipientColumn, remaining int) (int, error) {
+	b.Rows, b.Csv = nil, ""
+
+	fields, err := json.Marshal(b)
+
+	if err != nil {
+		return 0, fmt.Errorf("error marshalling body: %s", err)
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	bw.Write(fields[:len(fields)-1])
+	bw.WriteString(`,"rows":[`)
+
+	if err := enc.Encode(header); err != nil {
+		return 0, err
+	}
+
+	rows, number := 0, rowNumber(b.Source, header, 1)
+
+	for {
+		row, err := b.Source.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return rows, fmt.Errorf("error reading row after %d: %w", number, err)
+		}
+
+		number = rowNumber(b.Source, row, number+1)
+
+		if isBlankRow(row) {
+			continue
+		}
+
+		if err := checkRecipients(row, number, recipients); err != nil {
+			return rows, err
+		}
+
+		rows++
+
+		if rows > MaxBulkRows {
+			return rows, fmt.Errorf("%w: more than %d rows", ErrInvalidBulk, MaxBulkRows)
+		}
+
+		if remaining >= 0 && rows > remaining {
+			return rows, fmt.Errorf("%w: more than the %d notifications left today", ErrDailyLimitExceeded, remaining)
+		}
+
+		bw.WriteByte(',')
+
+		if err := enc.Encode(row); err != nil {
+			return rows, err
+		}
+	}
+
+	bw.WriteString("]}")
+
+	return rows, bw.Flush()
+}
@@ -0,0 +1,270 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+// readRows returns every row of the source of b.
+func readRows(t *testing.T, b Bulk) [][]string {
+	t.Helper()
+
+	var rows [][]string
+
+	for {
+		row, err := b.Source.Read()
+
+		if err == io.EOF {
+			return rows
+		}
+
+		if err != nil {
+			t.Fatalf("Read returned an error: %s", err)
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+func TestNewBulkFromCSVEncodings(t *testing.T) {
+	t.Parallel()
+
+	want := [][]string{{"email address", "nom"}, {"élise@example.com", "Élise Côté"}, {"sam@example.com", "Sam – l’équipe"}}
+
+	cases := map[string]string{
+		"utf-8":        "email address,nom\nélise@example.com,Élise Côté\nsam@example.com,Sam – l’équipe\n",
+		"utf-8 bom":    "\xef\xbb\xbfemail address,nom\r\nélise@example.com,Élise Côté\r\nsam@example.com,Sam – l’équipe\r\n",
+		"windows-1252": "email address;nom\r\n\xe9lise@example.com;\xc9lise C\xf4t\xe9\r\nsam@example.com;Sam \x96 l\x92\xe9quipe\r\n",
+	}
+
+	for name, input := range cases {
+		b, err := NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader(input))
+
+		if err != nil {
+			t.Fatalf("%s: NewBulkFromCSV returned an error: %s", name, err)
+		}
+
+		if got := readRows(t, b); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: rows = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestNewBulkFromTSV(t *testing.T) {
+	t.Parallel()
+
+	b, _ := NewBulkFromTSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader("phone number\tname\n+16135550123\tAlex \"Al\" Smith\n"))
+
+	want := [][]string{{"phone number", "name"}, {"+16135550123", `Alex "Al" Smith`}}
+
+	if got := readRows(t, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
+
+func TestSendBulkStream(t *testing.T) {
+	t.Parallel()
+
+	var got struct {
+		Name       string     `json:"name"`
+		TemplateId string     `json:"template_id"`
+		ReplyToId  string     `json:"reply_to_id"`
+		Rows       [][]string `json:"rows"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Error decoding request body: %s", err)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data": {"id": "11111111-1111-1111-1111-111111111111"}}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.LimitTracker = &LimitTracker{Limits: map[Channel]int{ChannelSms: 10}}
+
+	b, _ := NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader(" Phone_Number ,First  name,,\n+16135550123,Alex,,\n,,,\n+16135550124,Sam,,\n"))
+	b.ReplyToId = "22222222-2222-2222-2222-222222222222"
+
+	resp, err := c.SendBulk(b)
+
+	if err != nil {
+		t.Fatalf("SendBulk returned an error: %s", err)
+	}
+
+	if resp.StatusCode != 201 || resp.Data.Id != "11111111-1111-1111-1111-111111111111" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+
+	want := [][]string{{"phone number", "First name"}, {"+16135550123", "Alex", "", ""}, {"+16135550124", "Sam", "", ""}}
+
+	if got.Name != "Test" || got.ReplyToId != "22222222-2222-2222-2222-222222222222" || !reflect.DeepEqual(got.Rows, want) {
+		t.Errorf("Unexpected request body %+v", got)
+	}
+
+	if remaining, _ := c.LimitTracker.Remaining(ChannelSms); remaining != 8 {
+		t.Errorf("Expected 8 SMS remaining, got %d", remaining)
+	}
+}
+
+func TestSendBulkStreamHoldsLimit(t *testing.T) {
+	t.Parallel()
+
+	received, respond := make(chan struct{}), make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		close(received)
+		<-respond
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data": {"id": "11111111-1111-1111-1111-111111111111"}}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.LimitTracker = &LimitTracker{Limits: map[Channel]int{ChannelEmail: 3}}
+
+	done := make(chan error)
+
+	go func() {
+		b, _ := NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader("email address\na@example.com\n"))
+		_, err := c.SendBulk(b)
+		done <- err
+	}()
+
+	<-received
+
+	// The rest of the day's limit is held until the send is done
+	if remaining, _ := c.LimitTracker.Remaining(ChannelEmail); remaining != 0 {
+		t.Errorf("Expected 0 emails remaining during the send, got %d", remaining)
+	}
+
+	b, _ := NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader("email address\nb@example.com\n"))
+
+	if _, err := c.SendBulk(b); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Errorf("Expected ErrDailyLimitExceeded during another send, got %v", err)
+	}
+
+	close(respond)
+
+	if err := <-done; err != nil {
+		t.Fatalf("SendBulk returned an error: %s", err)
+	}
+
+	if remaining, _ := c.LimitTracker.Remaining(ChannelEmail); remaining != 2 {
+		t.Errorf("Expected 2 emails remaining, got %d", remaining)
+	}
+}
+
+func TestSendBulkStreamDefers(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request while the limit is reached")
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.LimitTracker = &LimitTracker{Limits: map[Channel]int{ChannelEmail: 1}, Defer: true}
+	c.LimitTracker.Seed(ChannelEmail, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	b, _ := NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader("email address\na@example.com\n"))
+
+	if _, err := c.SendBulkContext(ctx, b); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected to wait for the next day until the deadline, got %v", err)
+	}
+}
+
+func TestSendBulkStreamReportsAttempt(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer server.Close()
+
+	var attempts []RetryAttempt
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.Retry = &RetryPolicy{OnAttempt: func(a RetryAttempt) { attempts = append(attempts, a) }}
+
+	b, _ := NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader("email address\na@example.com\n"))
+
+	if _, err := c.SendBulk(b); err == nil {
+		t.Errorf("Expected an error for a 503 response")
+	}
+
+	// The body can't be replayed, so there is a single attempt
+	want := []RetryAttempt{{Method: "POST", Endpoint: "/v2/notifications/bulk", Attempt: 1, StatusCode: 503}}
+
+	if !reflect.DeepEqual(attempts, want) {
+		t.Errorf("Expected attempts %+v, got %+v", want, attempts)
+	}
+}
+
+func TestSendBulkStreamStopsAtInvalidRow(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.LimitTracker = &LimitTracker{Limits: map[Channel]int{ChannelEmail: 2}}
+
+	b, _ := NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader("email address,name\na@example.com,Alex\n,Sam\n"))
+
+	if _, err := c.SendBulk(b); !errors.Is(err, ErrInvalidBulk) || !strings.Contains(err.Error(), "row 3 has no email address") {
+		t.Errorf("Expected ErrInvalidBulk for row 3, got %v", err)
+	}
+
+	b, _ = NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader("email address\na@example.com\nb@example.com\nc@example.com\n"))
+
+	if _, err := c.SendBulk(b); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Errorf("Expected ErrDailyLimitExceeded, got %v", err)
+	}
+
+	if remaining, _ := c.LimitTracker.Remaining(ChannelEmail); remaining != 2 {
+		t.Errorf("Expected 2 emails remaining, got %d", remaining)
+	}
+
+	b, _ = NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader("phone number\n"+strings.Repeat("+16135550123\n", MaxBulkRows+1)))
+
+	if _, err := c.SendBulk(b); !errors.Is(err, ErrInvalidBulk) || !strings.Contains(err.Error(), "more than 50000 rows") {
+		t.Errorf("Expected ErrInvalidBulk past MaxBulkRows, got %v", err)
+	}
+
+	b, _ = NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader(""))
+
+	if _, err := c.SendBulk(b); !errors.Is(err, ErrInvalidBulk) {
+		t.Errorf("Expected ErrInvalidBulk for an empty file, got %v", err)
+	}
+}
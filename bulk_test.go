@@ -0,0 +1,173 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestSendBulkSms(t *testing.T) {
+	t.Parallel()
+
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.URL.Path != "/v2/notifications/bulk" {
+			t.Errorf("Expected request to /v2/notifications/bulk, got %s", r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data": {"id": "00000000-0000-0000-0000-000000000000", "notification_count": 2}}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.LimitTracker = &LimitTracker{
+		Limits: map[Channel]int{ChannelEmail: 10, ChannelSms: 10},
+	}
+
+	got, err := c.SendBulk(Bulk{
+		Name:       "Test",
+		TemplateId: "00000000-0000-0000-0000-000000000000",
+		Csv:        "Phone_Number,name\n+16135550123,Alex\n+16135550124,Sam\n",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected bulk SMS to be sent, got %s", err)
+	}
+
+	if got.StatusCode != 201 || got.Data.NotificationCount != 2 {
+		t.Errorf("Unexpected response %+v", got)
+	}
+
+	if remaining, _ := c.LimitTracker.Remaining(ChannelSms); remaining != 8 {
+		t.Errorf("Expected 8 SMS remaining, got %d", remaining)
+	}
+
+	if remaining, _ := c.LimitTracker.Remaining(ChannelEmail); remaining != 10 {
+		t.Errorf("Expected 10 emails remaining, got %d", remaining)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+
+	// The postcode of a letter can be its last address line
+	_, err = c.SendBulk(Bulk{
+		Name:       "Test",
+		TemplateId: "00000000-0000-0000-0000-000000000000",
+		Rows:       [][]string{{"address line 1", "address line 2", "address line 3", "postcode"}, {"Alex", "1 Main St", "Ottawa ON  K1A 0B1", ""}},
+	})
+
+	if err != nil {
+		t.Errorf("Expected bulk letters to be sent, got %s", err)
+	}
+}
+
+func TestSendBulkSkipsBlankRows(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data": {"id": "00000000-0000-0000-0000-000000000000"}}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.LimitTracker = &LimitTracker{
+		Limits: map[Channel]int{ChannelEmail: 10},
+	}
+
+	for name, b := range map[string]Bulk{
+		"rows": {Rows: [][]string{{"email address", "name"}, {"a@example.com", "Alex"}, {"", " "}, {"b@example.com", "Sam"}}},
+		"csv":  {Csv: "email address,name\na@example.com,Alex\n,,\nb@example.com,Sam\n"},
+	} {
+		b.Name, b.TemplateId = "Test", "00000000-0000-0000-0000-000000000000"
+
+		if _, err := c.SendBulk(b); err != nil {
+			t.Errorf("%s: Expected blank rows to be skipped, got %s", name, err)
+		}
+	}
+
+	// Only the 4 rows with a recipient count against the limit
+	if remaining, _ := c.LimitTracker.Remaining(ChannelEmail); remaining != 6 {
+		t.Errorf("Expected 6 emails remaining, got %d", remaining)
+	}
+}
+
+func TestSendBulkChecksColumns(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request to be sent")
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	cases := map[string]struct {
+		bulk Bulk
+		want string
+	}{
+		"no recipient column": {
+			Bulk{Rows: [][]string{{"name"}, {"Alex"}}},
+			"no email address, phone number or address line 1 column",
+		},
+		"ambiguous recipient columns": {
+			Bulk{Rows: [][]string{{"email address", "phone number"}, {"test@test.com", "+16135550123"}}},
+			"set Type to choose",
+		},
+		"missing column for type": {
+			Bulk{Rows: [][]string{{"email address"}, {"test@test.com"}}, Type: TemplateTypeSms},
+			`sms jobs need a "phone number" column`,
+		},
+		"missing letter address line": {
+			Bulk{Rows: [][]string{{"address_line_1", "postcode"}, {"1 Main St", "K1A 0B1"}}},
+			`letter jobs need a "address line 2" column`,
+		},
+		"missing letter postcode column": {
+			Bulk{Rows: [][]string{{"address line 1", "address line 2"}, {"Alex", "1 Main St"}}},
+			`letter jobs need a "postcode" or "address line 3" to "address line 7" column`,
+		},
+		"empty letter postcode": {
+			Bulk{Rows: [][]string{{"address line 1", "address line 2", "address line 3", "postcode"}, {"Alex", "1 Main St", "", " "}}},
+			"row 2 has no postcode",
+		},
+		"empty header": {
+			Bulk{Rows: [][]string{{"", " "}, {"test@test.com", "Alex"}}},
+			"the header has no column names",
+		},
+		"empty recipient": {
+			Bulk{Csv: "email address,name\ntest@test.com,Alex\n,Sam\n"},
+			"row 3 has no email address",
+		},
+		"empty recipient after a blank line": {
+			Bulk{Csv: "email address,name\ntest@test.com,Alex\n\n,Sam\n"},
+			"row 4 has no email address",
+		},
+		"short row": {
+			Bulk{Rows: [][]string{{"name", "phone number"}, {"Alex"}}},
+			"row 2 has no phone number",
+		},
+	}
+
+	for name, tc := range cases {
+		_, err := c.SendBulk(tc.bulk)
+
+		if !errors.Is(err, ErrInvalidBulk) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected ErrInvalidBulk containing %q, got %v", name, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Callbacks larger than this are rejected
+const maxCallbackBodySize = 1 << 20
+
+// DeliveryStatus is the body of a delivery receipt sent by Notify to a
+// service's callback URL.
+type DeliveryStatus struct {
+	Id                string             `json:"id"`
+	Reference         string             `json:"reference"`
+	To                string             `json:"to"`
+	Status            NotificationStatus `json:"status"`
+	StatusDescription string             `json:"status_description"`
+	ProviderResponse  string             `json:"provider_response"`
+	CreatedAt         time.Time          `json:"created_at"`
+	CompletedAt       time.Time          `json:"completed_at"`
+	SentAt            time.Time          `json:"sent_at"`
+	NotificationType  TemplateType       `json:"notification_type"`
+}
+
+// DeliveryStatusHandler receives delivery receipts from Notify. It responds
+// with:
+//   - 401 when the bearer token doesn't match one of Tokens
+//   - 400 when the body isn't a delivery receipt
+//   - 500 when Handle returns an error, so Notify sends it again
+//   - 200 when Handle succeeds, or the receipt was already handled
+type DeliveryStatusHandler struct {
+	// Accepted bearer tokens, set more than one while rotating them
+	Tokens []string
+
+	// Optional, called for every new receipt
+	Handle func(ctx context.Context, s DeliveryStatus) error
+
+	// Optional, receipts seen before are acknowledged without calling Handle
+	Replays *ReplayCache
+
+	// Optional, email addresses that permanently fail are added to it
+	Suppressions SuppressionList
+}
+
+func (h DeliveryStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveCallback(w, r, h.Tokens, h.Replays, func(s DeliveryStatus) (string, error) {
+		if s.Id == "" {
+			return "", errors.New("missing id")
+		}
+
+		if !s.Status.IsValid() {
+			return "", errors.New("invalid status")
+		}
+
+		return s.Id + "/" + string(s.Status), nil
+	}, func(ctx context.Context, s DeliveryStatus) error {
+		if h.Suppressions != nil && s.NotificationType == TemplateTypeEmail && s.Status == StatusPermanentFailure {
+			if err := h.Suppressions.Suppress(s.To, SuppressionPermanentFailure); err != nil {
+				return err
+			}
+		}
+
+		if h.Handle == nil {
+			return nil
+		}
+
+		return h.Handle(ctx, s)
+	})
+}
+
+// Complaint is the body of the callback sent by Notify when a recipient
+// marks an email as spam.
+type Complaint struct {
+	NotificationId string    `json:"notification_id"`
+	ComplaintId    string    `json:"complaint_id"`
+	Reference      string    `json:"reference"`
+	To             string    `json:"to"`
+	ComplaintDate  time.Time `json:"complaint_date"`
+}
+
+// ComplaintHandler receives complaint callbacks from Notify, with the same
+// responses as DeliveryStatusHandler.
+type ComplaintHandler struct {
+	// Accepted bearer tokens, set more than one while rotating them
+	Tokens []string
+
+	// Optional, called for every new complaint
+	Handle func(ctx context.Context, c Complaint) error
+
+	// Optional, complaints seen before are acknowledged without calling Handle
+	Replays *ReplayCache
+
+	// Optional, email addresses that complained are added to it
+	Suppressions SuppressionList
+}
+
+func (h ComplaintHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveCallback(w, r, h.Tokens, h.Replays, func(c Complaint) (string, error) {
+		if c.ComplaintId == "" {
+			return "", errors.New("missing complaint_id")
+		}
+
+		return "complaint/" + c.ComplaintId, nil
+	}, func(ctx context.Context, c Complaint) error {
+		if h.Suppressions != nil && c.To != "" {
+			if err := h.Suppressions.Suppress(c.To, SuppressionComplaint); err != nil {
+				return err
+			}
+		}
+
+		if h.Handle == nil {
+			return nil
+		}
+
+		return h.Handle(ctx, c)
+	})
+}
+
+// serveCallback verifies and decodes a callback from Notify, then passes it
+// to handle unless replays has seen its key before.
+func serveCallback[T any](w http.ResponseWriter, r *http.Request, tokens []string, replays *ReplayCache, key func(T) (string, error), handle func(context.Context, T) error) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !verifyBearerToken(r, tokens) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var payload T
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCallbackBodySize)).Decode(&payload); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	k, err := key(payload)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if replays != nil && !replays.begin(k) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := handle(r.Context(), payload); err != nil {
+		if replays != nil {
+			replays.forget(k)
+		}
+
+		http.Error(w, "error handling callback", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifyBearerToken compares the bearer token against every token in
+// constant time, so the time taken doesn't reveal which one nearly matched.
+func verifyBearerToken(r *http.Request, tokens []string) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	if !ok || token == "" {
+		return false
+	}
+
+	match := 0
+
+	for _, t := range tokens {
+		if t != "" {
+			match |= subtle.ConstantTimeCompare([]byte(token), []byte(t))
+		}
+	}
+
+	return match == 1
+}
+
+// ReplayCache remembers callbacks that were handled so replays from Notify
+// can be ignored. It's safe for concurrent use and the zero value is ready
+// to use.
+type ReplayCache struct {
+	// Optional, how long a callback is remembered, defaults to 24 hours
+	TTL time.Duration
+
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastPrune time.Time
+}
+
+// begin records key and reports whether it is new. A callback that is being
+// handled counts as seen, so concurrent replays are only handled once.
+func (c *ReplayCache) begin(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ttl := c.TTL
+
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	now := time.Now()
+
+	if c.seen == nil {
+		c.seen = map[string]time.Time{}
+	}
+
+	if now.Sub(c.lastPrune) > time.Minute {
+		for k, expires := range c.seen {
+			if now.After(expires) {
+				delete(c.seen, k)
+			}
+		}
+
+		c.lastPrune = now
+	}
+
+	if expires, ok := c.seen[key]; ok && now.Before(expires) {
+		return false
+	}
+
+	c.seen[key] = now.Add(ttl)
+
+	return true
+}
+
+// forget removes key so the callback is handled when it is sent again.
+func (c *ReplayCache) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.seen, key)
+}
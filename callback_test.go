@@ -0,0 +1,220 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+const deliveryStatusBody = `{
+	"id": "00000000-0000-0000-0000-000000000000",
+	"reference": null,
+	"to": "test@test.com",
+	"status": "delivered",
+	"status_description": "Delivered",
+	"provider_response": null,
+	"created_at": "2024-01-01T12:00:00.000000Z",
+	"completed_at": "2024-01-01T12:00:05.000000Z",
+	"sent_at": "2024-01-01T12:00:01.000000Z",
+	"notification_type": "email"
+}`
+
+func postCallback(h http.Handler, token string, body string) int {
+	r := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w.Code
+}
+
+func TestDeliveryStatusHandler(t *testing.T) {
+	t.Parallel()
+
+	var got []DeliveryStatus
+
+	h := DeliveryStatusHandler{
+		Tokens: []string{"old-token", "new-token"},
+		Handle: func(ctx context.Context, s DeliveryStatus) error {
+			got = append(got, s)
+			return nil
+		},
+	}
+
+	for _, token := range []string{"old-token", "new-token"} {
+		if code := postCallback(h, token, deliveryStatusBody); code != http.StatusOK {
+			t.Errorf("Expected 200 for %s, got %d", token, code)
+		}
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 callbacks, got %d", len(got))
+	}
+
+	want := DeliveryStatus{
+		Id:                "00000000-0000-0000-0000-000000000000",
+		To:                "test@test.com",
+		Status:            StatusDelivered,
+		StatusDescription: "Delivered",
+		CreatedAt:         time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		CompletedAt:       time.Date(2024, 1, 1, 12, 0, 5, 0, time.UTC),
+		SentAt:            time.Date(2024, 1, 1, 12, 0, 1, 0, time.UTC),
+		NotificationType:  TemplateTypeEmail,
+	}
+
+	if got[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, got[0])
+	}
+}
+
+func TestDeliveryStatusHandlerRejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	h := DeliveryStatusHandler{
+		Tokens: []string{"token"},
+		Handle: func(ctx context.Context, s DeliveryStatus) error {
+			t.Errorf("Expected Handle not to be called")
+			return nil
+		},
+	}
+
+	if code := postCallback(h, "wrong", deliveryStatusBody); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong token, got %d", code)
+	}
+
+	if code := postCallback(h, "", deliveryStatusBody); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a missing token, got %d", code)
+	}
+
+	if code := postCallback(h, "token", "<html>"); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid body, got %d", code)
+	}
+
+	if code := postCallback(h, "token", `{"id": "1", "status": "delivred"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid status, got %d", code)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/callback", nil))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for a GET request, got %d", w.Code)
+	}
+}
+
+func TestDeliveryStatusHandlerReplays(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	fail := true
+
+	h := DeliveryStatusHandler{
+		Tokens:  []string{"token"},
+		Replays: &ReplayCache{},
+		Handle: func(ctx context.Context, s DeliveryStatus) error {
+			calls++
+
+			if fail {
+				return errors.New("database unavailable")
+			}
+
+			return nil
+		},
+	}
+
+	// Failures are reported so Notify sends the callback again
+	if code := postCallback(h, "token", deliveryStatusBody); code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 when Handle fails, got %d", code)
+	}
+
+	fail = false
+
+	for i := 0; i < 2; i++ {
+		if code := postCallback(h, "token", deliveryStatusBody); code != http.StatusOK {
+			t.Errorf("Expected 200, got %d", code)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected Handle to be called twice, got %d", calls)
+	}
+}
+
+func TestComplaintHandlerSuppressesAddress(t *testing.T) {
+	t.Parallel()
+
+	var got Complaint
+
+	suppressions := &MemorySuppressionList{}
+
+	h := ComplaintHandler{
+		Tokens:       []string{"token"},
+		Suppressions: suppressions,
+		Handle: func(ctx context.Context, c Complaint) error {
+			got = c
+			return nil
+		},
+	}
+
+	body := `{
+		"notification_id": "00000000-0000-0000-0000-000000000000",
+		"complaint_id": "11111111-1111-1111-1111-111111111111",
+		"reference": "ref",
+		"to": "test@test.com",
+		"complaint_date": "2024-01-01T12:00:00.000000Z"
+	}`
+
+	if code := postCallback(h, "token", body); code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", code)
+	}
+
+	want := Complaint{
+		NotificationId: "00000000-0000-0000-0000-000000000000",
+		ComplaintId:    "11111111-1111-1111-1111-111111111111",
+		Reference:      "ref",
+		To:             "test@test.com",
+		ComplaintDate:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	if suppressed, _ := suppressions.IsSuppressed("test@test.com"); !suppressed {
+		t.Errorf("Expected test@test.com to be suppressed")
+	}
+
+	if code := postCallback(h, "token", `{"to": "test@test.com"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a missing complaint_id, got %d", code)
+	}
+}
+
+func TestDeliveryStatusHandlerSuppressesPermanentFailures(t *testing.T) {
+	t.Parallel()
+
+	suppressions := &MemorySuppressionList{}
+
+	h := DeliveryStatusHandler{
+		Tokens:       []string{"token"},
+		Suppressions: suppressions,
+	}
+
+	postCallback(h, "token", deliveryStatusBody)
+
+	if suppressed, _ := suppressions.IsSuppressed("test@test.com"); suppressed {
+		t.Errorf("Expected delivered address not to be suppressed")
+	}
+
+	postCallback(h, "token", strings.Replace(deliveryStatusBody, `"delivered"`, `"permanent-failure"`, 1))
+
+	if suppressed, _ := suppressions.IsSuppressed("test@test.com"); !suppressed {
+		t.Errorf("Expected permanently failed address to be suppressed")
+	}
+}
@@ -4,9 +4,14 @@ package client
 
 import (
 	"bytes"
-	"errors"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -14,6 +19,27 @@ type Client struct {
 	ApiKey     string
 	HttpClient http.Client
 	Hostname   string
+
+	// Optional, requests are attempted once when nil
+	Retry *RetryPolicy
+
+	// Optional, return an *APIError for non-2xx responses
+	Strict bool
+
+	// Optional, defaults to the ApiKey-v1 scheme using ApiKey
+	Authenticator Authenticator
+
+	// Optional, requests aren't paced when nil
+	RateLimiter *RateLimiter
+
+	// Optional, daily limits aren't checked when nil
+	LimitTracker *LimitTracker
+
+	// Optional, emails to addresses on the list aren't sent
+	Suppressions SuppressionList
+
+	// Optional, templates aren't cached or pinned when nil
+	Templates *TemplateCache
 }
 
 type ResponseError struct {
@@ -42,8 +68,8 @@ type Response struct {
 
 func NewClient(apiKey string) (Client, error) {
 	// Validate API key
-	if !validateApiKey(apiKey) {
-		return Client{}, errors.New("invalid API key")
+	if _, err := ParseApiKey(apiKey); err != nil {
+		return Client{}, err
 	}
 
 	// Set default hostname
@@ -57,39 +83,178 @@ func NewClient(apiKey string) (Client, error) {
 }
 
 func (c Client) DoGetRequest(endpoint string) (*http.Response, error) {
-	method := "GET"
+	return c.DoGetRequestContext(context.Background(), endpoint)
+}
+
+func (c Client) DoGetRequestContext(ctx context.Context, endpoint string) (*http.Response, error) {
+	return c.doRequest(ctx, allChannels, "GET", endpoint, nil)
+}
+
+func (c Client) DoPostRequest(endpoint string, body []byte) (*http.Response, error) {
+	return c.DoPostRequestContext(context.Background(), endpoint, body)
+}
+
+func (c Client) DoPostRequestContext(ctx context.Context, endpoint string, body []byte) (*http.Response, error) {
+	return c.doRequest(ctx, allChannels, "POST", endpoint, body)
+}
+
+// doRequest sends a request to the Notify API, retrying it according to
+// c.Retry. If the request fails because ctx was cancelled or its deadline
+// passed, ctx.Err() is returned as-is so callers can tell it apart from
+// transport and API errors.
+func (c Client) doRequest(ctx context.Context, ch Channel, method string, endpoint string, body []byte) (*http.Response, error) {
+	if c.Retry == nil {
+		return c.doAttempt(ctx, ch, method, endpoint, body)
+	}
+
+	return c.Retry.do(ctx, method, endpoint, func() (*http.Response, error) {
+		return c.doAttempt(ctx, ch, method, endpoint, body)
+	})
+}
 
-	resource := fmt.Sprintf("%s%s", c.Hostname, endpoint)
+// doAttempt sends a single request. body is wrapped in a new reader on every
+// call so the request can be safely replayed.
+func (c Client) doAttempt(ctx context.Context, ch Channel, method string, endpoint string, body []byte) (*http.Response, error) {
+	var reader io.Reader
 
-	req, err := http.NewRequest(method, resource, nil)
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	return c.doStream(ctx, ch, method, endpoint, reader)
+}
+
+// doStream sends a single request with a body that can only be read once,
+// waiting for c.RateLimiter first. It isn't retried.
+func (c Client) doStream(ctx context.Context, ch Channel, method string, endpoint string, body io.Reader) (*http.Response, error) {
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx, ch); err != nil {
+			return nil, err
+		}
+	}
+
+	resource := fmt.Sprintf("%s%s", strings.TrimSuffix(c.Hostname, "/"), endpoint)
+
+	req, err := http.NewRequestWithContext(ctx, method, resource, body)
 
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("ApiKey-v1 %s", c.ApiKey))
+	if method == "POST" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	var auth Authenticator = ApiKeyAuthenticator{ApiKey: c.ApiKey}
+
+	if c.Authenticator != nil {
+		auth = c.Authenticator
+	}
+
+	if err := auth.Authenticate(req); err != nil {
+		return nil, fmt.Errorf("error authenticating request: %w", err)
+	}
 
-	return c.HttpClient.Do(req)
+	resp, err := c.HttpClient.Do(req)
+
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if c.RateLimiter != nil && resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		d, _ := retryAfter(resp)
+		c.RateLimiter.Throttle(d)
+	}
+
+	return resp, err
 }
 
-func (c Client) DoPostRequest(endpoint string, body []byte) (*http.Response, error) {
-	method := "POST"
-	contentType := "application/json"
+// doGet calls a GET endpoint and decodes its response. name describes the
+// endpoint in errors.
+func doGet[T any](ctx context.Context, c Client, name string, url string, response T) (T, int, error) {
+	resp, err := c.DoGetRequestContext(ctx, url)
 
-	resource := fmt.Sprintf("%s%s", c.Hostname, endpoint)
+	if err != nil {
+		return response, 0, fmt.Errorf("error calling %s endpoint: %w", name, err)
+	}
 
-	req, err := http.NewRequest(method, resource, bytes.NewBuffer(body))
+	raw, err := readResponse(resp, &response)
 
 	if err != nil {
-		return nil, err
+		return response, resp.StatusCode, fmt.Errorf("error decoding %s response: %w", name, err)
+	}
+
+	return response, resp.StatusCode, c.checkResponse(resp.StatusCode, raw)
+}
+
+// endpointFromLink turns a link returned by Notify, such as the next page of
+// a list, into an endpoint relative to c.Hostname. Absolute links are
+// followed by path only, as Notify doesn't always know the hostname it is
+// served on.
+func (c Client) endpointFromLink(link string) (string, error) {
+	u, err := url.Parse(link)
+
+	if err != nil {
+		return "", err
 	}
 
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("Authorization", fmt.Sprintf("ApiKey-v1 %s", c.ApiKey))
+	endpoint := u.RequestURI()
 
-	return c.HttpClient.Do(req)
+	if !u.IsAbs() && !strings.HasPrefix(link, "/") {
+		endpoint = "/" + link
+	}
+
+	if base, err := url.Parse(c.Hostname); err == nil && u.IsAbs() {
+		if prefix := strings.TrimSuffix(base.Path, "/"); prefix != "" {
+			endpoint = strings.TrimPrefix(endpoint, prefix)
+		}
+	}
+
+	return endpoint, nil
 }
 
-func validateApiKey(apiKey string) bool {
-	return len(apiKey) >= 72
+// readResponse decodes a JSON response body into v and returns the raw body.
+// Bodies that aren't JSON, such as an HTML error page from a load balancer,
+// are returned as an *UnexpectedResponseError.
+func readResponse(resp *http.Response, v any) ([]byte, error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return body, newUnexpectedResponseError(resp, body, err)
+	}
+
+	err = json.Unmarshal(body, v)
+
+	if err == nil {
+		return body, nil
+	}
+
+	// Servers don't always label JSON correctly, so the content type is only
+	// used to explain why decoding failed
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !isJSONContentType(contentType) {
+		err = fmt.Errorf("expected JSON, got %s", contentType)
+	}
+
+	return body, newUnexpectedResponseError(resp, body, err)
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// checkResponse returns an *APIError for non-2xx responses in strict mode.
+func (c Client) checkResponse(statusCode int, body []byte) error {
+	if !c.Strict || (statusCode >= 200 && statusCode < 300) {
+		return nil
+	}
+
+	return newAPIError(statusCode, body)
 }
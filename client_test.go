@@ -1,11 +1,14 @@
 package client_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	. "github.com/cds-snc/notification-go-client"
 )
@@ -19,14 +22,26 @@ func TestNewClientWithInvalidApiKey(t *testing.T) {
 		t.Errorf("Expected error, got nil")
 	}
 
-	if err.Error() != "invalid API key" {
-		t.Errorf("Expected error message to be invalid API key, got %s", err.Error())
+	if !errors.Is(err, ErrInvalidApiKey) {
+		t.Errorf("Expected error to be invalid API key, got %s", err.Error())
 	}
 }
 
-func TestNewClientWithValidApiKey(t *testing.T) {
+// Keys used to only need 72 characters. The secret of this key is 38
+// characters, which isn't a UUID, so it is now rejected.
+func TestNewClientWithMalformedSecret(t *testing.T) {
 	apiKey := "testing-00000000-0000-0000-0000-000000000000-00000000-0000-0000-0000-00000000000000"
 
+	_, err := NewClient(apiKey)
+
+	if !errors.Is(err, ErrInvalidApiKey) {
+		t.Errorf("Expected error to be invalid API key, got %v", err)
+	}
+}
+
+func TestNewClientWithValidApiKey(t *testing.T) {
+	apiKey := "testing-00000000-0000-0000-0000-000000000000-00000000-0000-0000-0000-000000000000"
+
 	client, err := NewClient(apiKey)
 
 	if err != nil {
@@ -137,3 +152,52 @@ func TestDoPostRequestWithNoError(t *testing.T) {
 	}
 
 }
+
+func TestDoGetRequestContextWithDeadlineExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Block until the client gives up
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := &Client{
+		ApiKey:     "test",
+		HttpClient: http.Client{},
+		Hostname:   server.URL,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	resp, err := client.DoGetRequestContext(ctx, "/v2/notifications/email")
+
+	if resp != nil {
+		t.Errorf("Expected no response, got %+v", resp)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestDoPostRequestContextWithCancelledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request to be made")
+	}))
+	defer server.Close()
+
+	client := &Client{
+		ApiKey:     "test",
+		HttpClient: http.Client{},
+		Hostname:   server.URL,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.DoPostRequestContext(ctx, "/v2/notifications/email", []byte("test"))
+
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
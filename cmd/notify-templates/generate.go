@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	client "github.com/cds-snc/notification-go-client"
+)
+
+// readTemplates reads every .json file under dir, each holding a template or
+// a list of templates as returned by the API. When a template appears more
+// than once the latest version is kept.
+func readTemplates(dir string) ([]client.Template, error) {
+	latest := map[string]client.Template{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+
+		body, err := os.ReadFile(path)
+
+		if err != nil {
+			return err
+		}
+
+		var list client.Templates
+
+		if err := json.Unmarshal(body, &list); err != nil || list.Templates == nil {
+			var t client.Template
+
+			if err := json.Unmarshal(body, &t); err != nil {
+				return fmt.Errorf("error decoding %s: %w", path, err)
+			}
+
+			list.Templates = []client.Template{t}
+		}
+
+		for _, t := range list.Templates {
+			if t.Id == "" {
+				return fmt.Errorf("template without an id in %s", path)
+			}
+
+			if current, ok := latest[t.Id]; !ok || t.Version > current.Version {
+				latest[t.Id] = t
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]client.Template, 0, len(latest))
+
+	for _, t := range latest {
+		templates = append(templates, t)
+	}
+
+	return templates, nil
+}
+
+type field struct {
+	Name        string
+	Key         string
+	Conditional bool
+}
+
+// generate returns the formatted source of a file declaring a struct for
+// every email and sms template. Letter templates are skipped.
+func generate(pkg string, templates []client.Template) ([]byte, error) {
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Name != templates[j].Name {
+			return templates[i].Name < templates[j].Name
+		}
+
+		return templates[i].Id < templates[j].Id
+	})
+
+	var body bytes.Buffer
+
+	needsStrconv := false
+	typeNames := map[string]bool{}
+
+	for _, t := range templates {
+		if t.Type != client.TemplateTypeEmail && t.Type != client.TemplateTypeSms {
+			continue
+		}
+
+		name := uniqueTypeName(identifier(t.Name), typeNames)
+
+		var texts []string
+
+		if t.Type == client.TemplateTypeEmail {
+			texts = append(texts, t.Subject)
+		}
+
+		fields := templateFields(append(texts, t.Body))
+
+		fmt.Fprintf(&body, "\n// %s is the %s template %q, generated from version %d.\n", name, t.Type, t.Name, t.Version)
+		fmt.Fprintf(&body, "type %s struct {\n", name)
+
+		for _, f := range fields {
+			typ := "string"
+
+			if f.Conditional {
+				typ = "bool"
+			}
+
+			fmt.Fprintf(&body, "%s %s // ((%s))\n", f.Name, typ, f.Key)
+		}
+
+		fmt.Fprintf(&body, "}\n\n")
+		fmt.Fprintf(&body, "const %sTemplateId = %q\n\n", name, t.Id)
+
+		if t.Type == client.TemplateTypeEmail {
+			fmt.Fprintf(&body, "func (t %s) ToEmail(address string) client.Email {\n", name)
+			fmt.Fprintf(&body, "return client.Email{\nEmailAddress: address,\nTemplateId: %sTemplateId,\n", name)
+			fmt.Fprintf(&body, "Personalisation: map[string]interface{}{\n")
+
+			for _, f := range fields {
+				fmt.Fprintf(&body, "%q: t.%s,\n", f.Key, f.Name)
+			}
+		} else {
+			fmt.Fprintf(&body, "func (t %s) ToSms(phoneNumber string) client.Sms {\n", name)
+			fmt.Fprintf(&body, "return client.Sms{\nPhoneNumber: phoneNumber,\nTemplateId: %sTemplateId,\n", name)
+			fmt.Fprintf(&body, "Personalisation: map[string]string{\n")
+
+			for _, f := range fields {
+				if f.Conditional {
+					needsStrconv = true
+					fmt.Fprintf(&body, "%q: strconv.FormatBool(t.%s),\n", f.Key, f.Name)
+				} else {
+					fmt.Fprintf(&body, "%q: t.%s,\n", f.Key, f.Name)
+				}
+			}
+		}
+
+		fmt.Fprintf(&body, "},\n}\n}\n")
+	}
+
+	var src bytes.Buffer
+
+	fmt.Fprintf(&src, "// Code generated by notify-templates. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(&src, "import (\n")
+
+	if needsStrconv {
+		fmt.Fprintf(&src, "%q\n\n", "strconv")
+	}
+
+	fmt.Fprintf(&src, "client %q\n)\n", "github.com/cds-snc/notification-go-client")
+	src.Write(body.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+
+	if err != nil {
+		return nil, fmt.Errorf("error formatting generated code: %w", err)
+	}
+
+	return formatted, nil
+}
+
+// templateFields returns a field per placeholder. A placeholder used both
+// as a value and as a condition is a string, as Notify accepts yes or no.
+func templateFields(texts []string) []field {
+	var fields []field
+
+	index := map[string]int{}
+
+	// Fields can't share a name with the methods of the struct
+	names := map[string]bool{"ToEmail": true, "ToSms": true, "TemplateId": true}
+
+	for _, text := range texts {
+		for _, p := range client.ParsePlaceholders(text) {
+			key := strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(p.Name))
+
+			if i, ok := index[key]; ok {
+				fields[i].Conditional = fields[i].Conditional && p.Conditional
+				continue
+			}
+
+			index[key] = len(fields)
+			fields = append(fields, field{
+				Name:        uniqueIdentifier(identifier(p.Name), names),
+				Key:         p.Name,
+				Conditional: p.Conditional,
+			})
+		}
+	}
+
+	return fields
+}
+
+// identifier turns a template or placeholder name into an exported Go
+// identifier, as in "first name" to FirstName.
+func identifier(name string) string {
+	var b strings.Builder
+
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		b.WriteString(strings.ToUpper(string(runes[0])) + string(runes[1:]))
+	}
+
+	id := b.String()
+
+	if id == "" || !unicode.IsLetter([]rune(id)[0]) {
+		id = "T" + id
+	}
+
+	return id
+}
+
+// uniqueTypeName returns id, or id with a number, such that neither it nor its
+// TemplateId const are used.
+func uniqueTypeName(id string, used map[string]bool) string {
+	unique := id
+
+	for i := 2; used[unique] || used[unique+"TemplateId"]; i++ {
+		unique = fmt.Sprintf("%s%d", id, i)
+	}
+
+	used[unique] = true
+	used[unique+"TemplateId"] = true
+
+	return unique
+}
+
+func uniqueIdentifier(id string, used map[string]bool) string {
+	unique := id
+
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", id, i)
+	}
+
+	used[unique] = true
+
+	return unique
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	client "github.com/cds-snc/notification-go-client"
+)
+
+// The client package is imported from source, once for every test
+var (
+	checkFset     = token.NewFileSet()
+	checkImporter = importer.ForCompiler(checkFset, "source", nil)
+)
+
+// typeCheck parses and type checks generated source.
+func typeCheck(t *testing.T, src []byte) {
+	t.Helper()
+
+	wd, _ := os.Getwd()
+	fset := checkFset
+
+	// Named in this directory so the client package resolves through go.mod
+	f, err := parser.ParseFile(fset, filepath.Join(wd, "gen.go"), src, 0)
+
+	if err != nil {
+		t.Fatalf("Expected generated code to parse, got %s\n%s", err, src)
+	}
+
+	conf := types.Config{Importer: checkImporter}
+
+	if _, err := conf.Check("notices", fset, []*ast.File{f}, nil); err != nil {
+		t.Fatalf("Expected generated code to type check, got %s\n%s", err, src)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	templates := []client.Template{
+		{Id: "1", Name: "Welcome email", Type: client.TemplateTypeEmail, Version: 3, Subject: "Hi ((first name))", Body: "((show link??Click here))\n((first_name)) ((2fa code))"},
+		{Id: "2", Name: "Reminder", Type: client.TemplateTypeSms, Version: 1, Body: "((urgent??Now!)) ((day))"},
+		{Id: "3", Name: "Letter", Type: client.TemplateTypeLetter, Body: "((address))"},
+	}
+
+	src, err := generate("notices", templates)
+
+	if err != nil {
+		t.Fatalf("generate returned an error: %s", err)
+	}
+
+	typeCheck(t, src)
+
+	for _, want := range []string{
+		"package notices",
+		"type WelcomeEmail struct",
+		"FirstName string",
+		"ShowLink  bool",
+		"T2faCode  string",
+		`"first name": t.FirstName,`,
+		"func (t WelcomeEmail) ToEmail(address string) client.Email",
+		"func (t Reminder) ToSms(phoneNumber string) client.Sms",
+		`"urgent": strconv.FormatBool(t.Urgent),`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected generated code to contain %q\n%s", want, src)
+		}
+	}
+
+	if strings.Contains(string(src), "type Letter") {
+		t.Errorf("Expected letter templates to be skipped")
+	}
+}
+
+func TestGenerateAvoidsNameClashes(t *testing.T) {
+	templates := []client.Template{
+		{Id: "1", Name: "Welcome", Type: client.TemplateTypeEmail, Body: "((to email)) ((to_sms)) ((template id))"},
+		{Id: "2", Name: "Welcome template id", Type: client.TemplateTypeSms, Body: "((to sms))"},
+		{Id: "3", Name: "Welcome", Type: client.TemplateTypeSms, Body: "hi"},
+	}
+
+	src, err := generate("notices", templates)
+
+	if err != nil {
+		t.Fatalf("generate returned an error: %s", err)
+	}
+
+	typeCheck(t, src)
+
+	for _, want := range []string{
+		"type Welcome struct",
+		"ToEmail2    string",
+		"ToSms2      string",
+		"TemplateId2 string",
+		"type Welcome2 struct",
+		"type WelcomeTemplateId2 struct",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected generated code to contain %q\n%s", want, src)
+		}
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"first name":    "FirstName",
+		"first_name":    "FirstName",
+		"Bienvenue été": "BienvenueÉté",
+		"2fa":           "T2fa",
+		"((?))":         "T",
+	}
+
+	for name, want := range cases {
+		if got := identifier(name); got != want {
+			t.Errorf("Expected identifier(%q) to be %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestReadTemplatesKeepsLatestVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(dir, "welcome"), 0o755)
+	os.WriteFile(filepath.Join(dir, "welcome", "v1.json"), []byte(`{"id": "1", "name": "Welcome", "version": 1}`), 0o644)
+	os.WriteFile(filepath.Join(dir, "welcome", "v2.json"), []byte(`{"id": "1", "name": "Welcome", "version": 2}`), 0o644)
+	os.WriteFile(filepath.Join(dir, "all.json"), []byte(`{"templates": [{"id": "2", "name": "Reminder", "version": 4}]}`), 0o644)
+
+	templates, err := readTemplates(dir)
+
+	if err != nil {
+		t.Fatalf("readTemplates returned an error: %s", err)
+	}
+
+	versions := map[string]int{}
+
+	for _, tmpl := range templates {
+		versions[tmpl.Id] = tmpl.Version
+	}
+
+	if len(versions) != 2 || versions["1"] != 2 || versions["2"] != 4 {
+		t.Errorf("Expected versions 2 and 4, got %v", versions)
+	}
+}
@@ -0,0 +1,244 @@
+// Command notify-templates works with the templates of a Notify service.
+//
+//	notify-templates generate [-templates dir] [-package name] [-out file]
+//	notify-templates export [-templates dir]
+//	notify-templates diff [-templates dir] id [from [to]]
+//
+// generate writes a Go struct for every email and sms template, with a field
+// per placeholder and a ToEmail or ToSms method. Templates are read from the
+// JSON files in -templates, or from the API using NOTIFY_API_KEY when it
+// isn't set. It's meant to be run with go generate:
+//
+//	//go:generate go run github.com/cds-snc/notification-go-client/cmd/notify-templates generate -templates ./templates
+//
+// export writes every version of every template to -templates, as
+// <template id>/v<version>.json, fetching only the versions not already there.
+//
+// diff compares two versions of a template, read from -templates or the API.
+// With one version it compares that version to the live template, and with
+// none it compares the latest exported version to the live template. It exits
+// with status 1 when they differ.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	client "github.com/cds-snc/notification-go-client"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "generate":
+		err = runGenerate(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if errors.Is(err, errChanged) {
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "notify-templates: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: notify-templates generate|export|diff [flags]")
+	os.Exit(2)
+}
+
+func runGenerate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+
+	dir := flags.String("templates", "", "directory of template JSON files, the API is used when empty")
+	hostname := flags.String("hostname", "https://api.notification.canada.ca", "Notify API hostname")
+	pkg := flags.String("package", os.Getenv("GOPACKAGE"), "package of the generated file")
+	out := flags.String("out", "notify_templates.go", "file to write")
+
+	flags.Parse(args)
+
+	if *pkg == "" {
+		return fmt.Errorf("-package is required outside of go generate")
+	}
+
+	templates, err := loadTemplates(*dir, *hostname)
+
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(*pkg, templates)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(*out, src, 0o644)
+}
+
+// loadTemplates reads templates from dir, or from the API when dir is empty.
+func loadTemplates(dir string, hostname string) ([]client.Template, error) {
+	if dir != "" {
+		return readTemplates(dir)
+	}
+
+	c, err := newClient(hostname)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.ListTemplatesContext(context.Background(), "")
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing templates: %w", err)
+	}
+
+	return resp.Templates, nil
+}
+
+// errChanged is returned by diff when the templates differ.
+var errChanged = errors.New("templates differ")
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+
+	dir := flags.String("templates", "templates", "directory to export templates to")
+	hostname := flags.String("hostname", "https://api.notification.canada.ca", "Notify API hostname")
+
+	flags.Parse(args)
+
+	c, err := newClient(*hostname)
+
+	if err != nil {
+		return err
+	}
+
+	written, err := c.ExportTemplatesContext(context.Background(), *dir)
+
+	for _, path := range written {
+		fmt.Println(path)
+	}
+
+	return err
+}
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+
+	dir := flags.String("templates", "templates", "directory of exported templates")
+	hostname := flags.String("hostname", "https://api.notification.canada.ca", "Notify API hostname")
+
+	flags.Parse(args)
+
+	if flags.NArg() < 1 || flags.NArg() > 3 {
+		return fmt.Errorf("usage: notify-templates diff [-templates dir] id [from [to]]")
+	}
+
+	id := flags.Arg(0)
+	versions := make([]int, flags.NArg()-1)
+
+	for i, arg := range flags.Args()[1:] {
+		v, err := strconv.Atoi(arg)
+
+		if err != nil || v < 1 {
+			return fmt.Errorf("invalid version %q", arg)
+		}
+
+		versions[i] = v
+	}
+
+	var (
+		from, to client.Template
+		err      error
+	)
+
+	switch len(versions) {
+	case 0:
+		if from, err = client.ReadExportedTemplate(*dir, id, 0); err == nil {
+			to, err = liveTemplate(*hostname, id, 0)
+		}
+	case 1:
+		if from, err = exportedOrLiveTemplate(*hostname, *dir, id, versions[0]); err == nil {
+			to, err = liveTemplate(*hostname, id, 0)
+		}
+	default:
+		if from, err = exportedOrLiveTemplate(*hostname, *dir, id, versions[0]); err == nil {
+			to, err = exportedOrLiveTemplate(*hostname, *dir, id, versions[1])
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	diff := client.DiffTemplates(from, to)
+
+	if diff == "" {
+		return nil
+	}
+
+	fmt.Print(diff)
+
+	return errChanged
+}
+
+// exportedOrLiveTemplate reads a version of a template from dir, falling back
+// to the API when it hasn't been exported.
+func exportedOrLiveTemplate(hostname string, dir string, id string, version int) (client.Template, error) {
+	t, err := client.ReadExportedTemplate(dir, id, version)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return liveTemplate(hostname, id, version)
+	}
+
+	return t, err
+}
+
+// liveTemplate gets a version of a template from the API, or its latest
+// version when version is 0.
+func liveTemplate(hostname string, id string, version int) (client.Template, error) {
+	c, err := newClient(hostname)
+
+	if err != nil {
+		return client.Template{}, err
+	}
+
+	if version == 0 {
+		return c.GetTemplateContext(context.Background(), id)
+	}
+
+	return c.GetTemplateVersionContext(context.Background(), id, version)
+}
+
+// newClient returns a strict client using the NOTIFY_API_KEY environment
+// variable.
+func newClient(hostname string) (client.Client, error) {
+	c, err := client.NewClient(os.Getenv("NOTIFY_API_KEY"))
+
+	if err != nil {
+		return client.Client{}, err
+	}
+
+	c.Hostname = hostname
+	c.Strict = true
+
+	return c, nil
+}
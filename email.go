@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -16,29 +17,59 @@ type Email struct {
 }
 
 func (c Client) SendEmail(e Email) (Response, error) {
-	body, err := json.Marshal(e)
+	return c.SendEmailContext(context.Background(), e)
+}
 
+func (c Client) SendEmailContext(ctx context.Context, e Email) (Response, error) {
 	var response Response
 
+	if c.Suppressions != nil {
+		suppressed, err := c.Suppressions.IsSuppressed(e.EmailAddress)
+
+		if err != nil {
+			return response, fmt.Errorf("error checking suppression list: %w", err)
+		}
+
+		if suppressed {
+			return response, ErrSuppressedRecipient
+		}
+	}
+
+	body, err := json.Marshal(e)
+
 	if err != nil {
 		return response, fmt.Errorf("error marshalling body: %s", err)
 	}
 
-	resp, err := c.DoPostRequest("/v2/notifications/email", body)
+	if err := c.checkPinnedVersion(ctx, e.TemplateId); err != nil {
+		return response, err
+	}
+
+	release, err := c.reserveDailyLimit(ctx, ChannelEmail, 1)
 
 	if err != nil {
-		return response, fmt.Errorf("error calling email endpoint: %s", err)
+		return response, err
 	}
 
-	defer resp.Body.Close()
+	defer func() { release(response.StatusCode) }()
 
-	err = json.NewDecoder(resp.Body).Decode(&response)
+	resp, err := c.doRequest(ctx, ChannelEmail, "POST", "/v2/notifications/email", body)
 
 	if err != nil {
-		return response, fmt.Errorf("error decoding email response: %s", err)
+		return response, fmt.Errorf("error calling email endpoint: %w", err)
 	}
 
+	raw, err := readResponse(resp, &response)
+
 	response.StatusCode = resp.StatusCode
 
-	return response, nil
+	if err != nil {
+		return response, fmt.Errorf("error decoding email response: %w", err)
+	}
+
+	if err := c.checkResponse(resp.StatusCode, raw); err != nil {
+		return response, err
+	}
+
+	return response, c.checkSentVersion(e.TemplateId, response.Template.Version)
 }
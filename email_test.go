@@ -1,7 +1,9 @@
 package client_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -85,3 +87,28 @@ func TestSendEmail(t *testing.T) {
 		t.Errorf("SendEmail() = %v, want %v", got, want)
 	}
 }
+
+func TestSendEmailContextWithCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request to be made")
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.SendEmailContext(ctx, Email{
+		EmailAddress: "test@test.com",
+		TemplateId:   "00000000-0000-0000-0000-000000000000",
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
+
+// windows1252 maps the bytes 0x80 to 0x9f of Windows-1252 to Unicode. The
+// other bytes are the same as in Latin-1, and so as their code point. Bytes
+// that aren't assigned map to the control character of the same value.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+}
+
+type textEncoding int
+
+const (
+	encodingUnknown textEncoding = iota
+	encodingUTF8
+	encodingWindows1252
+)
+
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// textReader decodes text that is either UTF-8 or Windows-1252, as exported
+// by Excel on French Canadian systems, into UTF-8. A byte order mark means
+// UTF-8 and is dropped. Otherwise the encoding is decided at the first byte
+// outside ASCII, which is the same in both, depending on whether it starts a
+// valid UTF-8 sequence.
+type textReader struct {
+	r        *bufio.Reader
+	encoding textEncoding
+	pending  []byte
+}
+
+func newTextReader(r io.Reader) (*textReader, error) {
+	t := &textReader{r: bufio.NewReader(r)}
+
+	start, err := t.r.Peek(len(utf8BOM))
+
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if bytes.Equal(start, utf8BOM) {
+		t.r.Discard(len(utf8BOM))
+		t.encoding = encodingUTF8
+	}
+
+	return t, nil
+}
+
+func (t *textReader) Read(p []byte) (int, error) {
+	if t.encoding == encodingUTF8 && len(t.pending) == 0 {
+		return t.r.Read(p)
+	}
+
+	n := 0
+
+	for n < len(p) {
+		if len(t.pending) > 0 {
+			c := copy(p[n:], t.pending)
+			t.pending = t.pending[c:]
+			n += c
+			continue
+		}
+
+		// Stop rather than block once something has been read
+		if n > 0 && t.r.Buffered() == 0 {
+			break
+		}
+
+		b, err := t.r.ReadByte()
+
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+
+			return 0, err
+		}
+
+		if b < utf8.RuneSelf {
+			p[n] = b
+			n++
+			continue
+		}
+
+		if t.encoding == encodingUnknown {
+			t.r.UnreadByte()
+			t.encoding = t.detect()
+
+			if t.encoding == encodingUTF8 {
+				if n == 0 {
+					return t.r.Read(p)
+				}
+
+				return n, nil
+			}
+
+			t.r.ReadByte()
+		}
+
+		r := rune(b)
+
+		if b < 0xa0 {
+			r = windows1252[b-0x80]
+		}
+
+		t.pending = utf8.AppendRune(nil, r)
+	}
+
+	return n, nil
+}
+
+// detect decides the encoding from the bytes starting at the first one
+// outside ASCII.
+func (t *textReader) detect() textEncoding {
+	next, _ := t.r.Peek(utf8.UTFMax)
+
+	if r, size := utf8.DecodeRune(next); r == utf8.RuneError && size <= 1 {
+		return encodingWindows1252
+	}
+
+	return encodingUTF8
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Categories of Notify API errors, for use with errors.Is on an *APIError.
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrAuth               = errors.New("authentication failed")
+	ErrRateLimited        = errors.New("rate limit exceeded")
+	ErrDailyLimitExceeded = errors.New("daily sending limit exceeded")
+	ErrTrialModeRecipient = errors.New("recipient not allowed in trial mode")
+	ErrServerError        = errors.New("server error")
+)
+
+// APIError is returned in strict mode when the Notify API responds with a
+// non-2xx status code.
+type APIError struct {
+	StatusCode int
+	Errors     []ResponseError
+	Body       []byte
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	var response struct {
+		Errors []ResponseError `json:"errors"`
+	}
+
+	// The body may not be JSON, in which case only the status code is known
+	json.Unmarshal(body, &response)
+
+	return &APIError{
+		StatusCode: statusCode,
+		Errors:     response.Errors,
+		Body:       body,
+	}
+}
+
+func (e *APIError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("notify API returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+
+	messages := make([]string, len(e.Errors))
+
+	for i, err := range e.Errors {
+		messages[i] = fmt.Sprintf("%s: %s", err.Error, err.Message)
+	}
+
+	return fmt.Sprintf("notify API returned %d: %s", e.StatusCode, strings.Join(messages, "; "))
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrAuth:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests && !e.isDailyLimit()
+	case ErrDailyLimitExceeded:
+		return e.StatusCode == http.StatusTooManyRequests && e.isDailyLimit()
+	case ErrTrialModeRecipient:
+		return e.StatusCode == http.StatusBadRequest && e.hasMessage("trial mode")
+	case ErrServerError:
+		return e.StatusCode >= 500
+	}
+
+	return false
+}
+
+// isDailyLimit tells apart the daily send limit errors ("Exceeded send limits
+// (50) for today", "Exceeded email daily sending limit of 50 messages") from
+// the per minute RateLimitError, which share the 429 status code.
+func (e *APIError) isDailyLimit() bool {
+	for _, err := range e.Errors {
+		if err.Error == "RateLimitError" {
+			return false
+		}
+	}
+
+	return e.hasMessage("for today") || e.hasMessage("daily")
+}
+
+func (e *APIError) hasMessage(s string) bool {
+	for _, err := range e.Errors {
+		if strings.Contains(strings.ToLower(err.Message), s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Only the start of an unexpected body is kept, enough to identify an error
+// page without holding on to all of it.
+const maxUnexpectedBodySize = 1024
+
+// UnexpectedResponseError is returned when a response body can't be decoded,
+// for example when a proxy returns an HTML error page instead of JSON.
+type UnexpectedResponseError struct {
+	StatusCode  int
+	Header      http.Header
+	ContentType string
+
+	// Truncated to the first 1024 bytes
+	Body []byte
+	Err  error
+}
+
+func newUnexpectedResponseError(resp *http.Response, body []byte, err error) *UnexpectedResponseError {
+	if len(body) > maxUnexpectedBodySize {
+		body = body[:maxUnexpectedBodySize]
+	}
+
+	return &UnexpectedResponseError{
+		StatusCode:  resp.StatusCode,
+		Header:      resp.Header,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+		Err:         err,
+	}
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	snippet := strings.Join(strings.Fields(string(e.Body)), " ")
+
+	if len(snippet) > 200 {
+		snippet = snippet[:200] + "..."
+	}
+
+	return fmt.Sprintf("unexpected %d %s response: %s: %q", e.StatusCode, http.StatusText(e.StatusCode), e.Err, snippet)
+}
+
+func (e *UnexpectedResponseError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the same status code categories as *APIError, so a 502 error
+// page is still an ErrServerError.
+func (e *UnexpectedResponseError) Is(target error) bool {
+	return (&APIError{StatusCode: e.StatusCode}).Is(target)
+}
@@ -0,0 +1,206 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		err    *APIError
+		target error
+		want   bool
+	}{
+		{
+			name:   "bad request",
+			err:    &APIError{StatusCode: 400, Errors: []ResponseError{{Error: "ValidationError", Message: "template_id is not a valid UUID"}}},
+			target: ErrBadRequest,
+			want:   true,
+		},
+		{
+			name:   "trial mode recipient",
+			err:    &APIError{StatusCode: 400, Errors: []ResponseError{{Error: "BadRequestError", Message: "Can't send to this recipient when service is in trial mode"}}},
+			target: ErrTrialModeRecipient,
+			want:   true,
+		},
+		{
+			name:   "bad request is not trial mode",
+			err:    &APIError{StatusCode: 400, Errors: []ResponseError{{Error: "ValidationError", Message: "template_id is not a valid UUID"}}},
+			target: ErrTrialModeRecipient,
+			want:   false,
+		},
+		{
+			name:   "auth",
+			err:    &APIError{StatusCode: 403, Errors: []ResponseError{{Error: "AuthError", Message: "Invalid token: API key not found"}}},
+			target: ErrAuth,
+			want:   true,
+		},
+		{
+			name:   "rate limited",
+			err:    &APIError{StatusCode: 429, Errors: []ResponseError{{Error: "RateLimitError", Message: "Exceeded rate limit for key type LIVE of 1000 requests per 60 seconds"}}},
+			target: ErrRateLimited,
+			want:   true,
+		},
+		{
+			name:   "rate limited is not daily limit",
+			err:    &APIError{StatusCode: 429, Errors: []ResponseError{{Error: "RateLimitError", Message: "Exceeded rate limit for key type LIVE of 1000 requests per 60 seconds"}}},
+			target: ErrDailyLimitExceeded,
+			want:   false,
+		},
+		{
+			name:   "daily limit",
+			err:    &APIError{StatusCode: 429, Errors: []ResponseError{{Error: "TooManyRequestsError", Message: "Exceeded send limits (50) for today"}}},
+			target: ErrDailyLimitExceeded,
+			want:   true,
+		},
+		{
+			name:   "server error",
+			err:    &APIError{StatusCode: 502},
+			target: ErrServerError,
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrictModeReturnsAPIError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status_code": 400, "errors": [{"error": "BadRequestError", "message": "Can't send to this recipient when service is in trial mode"}]}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	e := Email{
+		EmailAddress: "test@test.com",
+		TemplateId:   "00000000-0000-0000-0000-000000000000",
+	}
+
+	// Errors are only returned in strict mode
+	resp, err := c.SendEmail(e)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+
+	if resp.StatusCode != 400 {
+		t.Errorf("Expected status code to be 400, got %d", resp.StatusCode)
+	}
+
+	c.Strict = true
+
+	resp, err = c.SendEmail(e)
+
+	var apiErr *APIError
+
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %v", err)
+	}
+
+	if apiErr.StatusCode != 400 || len(apiErr.Errors) != 1 || len(apiErr.Body) == 0 {
+		t.Errorf("Expected APIError to contain the response, got %+v", apiErr)
+	}
+
+	if !errors.Is(err, ErrTrialModeRecipient) || !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Expected trial mode bad request error, got %s", err)
+	}
+
+	if resp.StatusCode != 400 || len(resp.Errors) != 1 {
+		t.Errorf("Expected response to be populated, got %+v", resp)
+	}
+}
+
+func TestUnexpectedResponseErrorForHTMLErrorPage(t *testing.T) {
+	t.Parallel()
+
+	page := "<html><body><h1>502 Bad Gateway</h1>" + strings.Repeat("<!-- padding -->", 100) + "</body></html>"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("X-Request-Id", "abc")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(page))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	resp, err := c.SendSms(Sms{
+		PhoneNumber: "1234567890",
+		TemplateId:  "00000000-0000-0000-0000-000000000000",
+	})
+
+	var unexpected *UnexpectedResponseError
+
+	if !errors.As(err, &unexpected) {
+		t.Fatalf("Expected *UnexpectedResponseError, got %v", err)
+	}
+
+	if unexpected.StatusCode != 502 || unexpected.ContentType != "text/html" {
+		t.Errorf("Expected a 502 text/html error, got %d %s", unexpected.StatusCode, unexpected.ContentType)
+	}
+
+	if unexpected.Header.Get("X-Request-Id") != "abc" {
+		t.Errorf("Expected headers to be preserved, got %v", unexpected.Header)
+	}
+
+	if len(unexpected.Body) != 1024 || !strings.HasPrefix(string(unexpected.Body), "<html>") {
+		t.Errorf("Expected body to be truncated to 1024 bytes, got %d", len(unexpected.Body))
+	}
+
+	if !errors.Is(err, ErrServerError) {
+		t.Errorf("Expected error to be a server error, got %s", err)
+	}
+
+	if resp.StatusCode != 502 {
+		t.Errorf("Expected status code to be 502, got %d", resp.StatusCode)
+	}
+}
+
+func TestUnexpectedResponseErrorPreservesStatusCodeForStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("<html>Service Unavailable</html>"))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	resp, err := c.GetStatusById("00000000-0000-0000-0000-000000000000")
+
+	if !errors.Is(err, ErrServerError) {
+		t.Errorf("Expected error to be a server error, got %v", err)
+	}
+
+	if resp.StatusCode != 503 {
+		t.Errorf("Expected status code to be 503, got %d", resp.StatusCode)
+	}
+}
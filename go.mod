@@ -1,5 +1,5 @@
 module github.com/cds-snc/notification-go-client
 
-go 1.21.5
+go 1.23
 
-require github.com/google/go-querystring v1.1.0 // indirect
+require github.com/google/go-querystring v1.1.0
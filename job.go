@@ -0,0 +1,303 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobStatusPending               JobStatus = "pending"
+	JobStatusScheduled             JobStatus = "scheduled"
+	JobStatusInProgress            JobStatus = "in progress"
+	JobStatusFinished              JobStatus = "finished"
+	JobStatusCancelled             JobStatus = "cancelled"
+	JobStatusSendingLimitsExceeded JobStatus = "sending limits exceeded"
+	JobStatusError                 JobStatus = "error"
+)
+
+// Job is the bulk job returned by SendBulk, with its timestamps parsed.
+type Job struct {
+	Id                 string
+	TemplateId         string
+	TemplateVersion    int
+	Status             JobStatus
+	NotificationCount  int
+	OriginalFileName   string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	ScheduledFor       time.Time
+	ProcessingStarted  time.Time
+	ProcessingFinished time.Time
+}
+
+// Job returns the job of a bulk response. Timestamps that are empty are left
+// as the zero time.
+func (r BulkResponse) Job() (Job, error) {
+	d := r.Data
+
+	job := Job{
+		Id:                d.Id,
+		TemplateId:        d.Template,
+		TemplateVersion:   d.TemplateVersion,
+		Status:            JobStatus(d.JobStatus),
+		NotificationCount: d.NotificationCount,
+		OriginalFileName:  d.OriginalFileName,
+	}
+
+	for _, ts := range []struct {
+		name  string
+		value string
+		t     *time.Time
+	}{
+		{"created_at", d.CreatedAt, &job.CreatedAt},
+		{"updated_at", d.UpdatedAt, &job.UpdatedAt},
+		{"scheduled_for", d.SecheduledFor, &job.ScheduledFor},
+		{"processing_started", d.ProcessingStarted, &job.ProcessingStarted},
+		{"processing_finished", d.ProcessingFinished, &job.ProcessingFinished},
+	} {
+		t, err := parseTimestamp(ts.value)
+
+		if err != nil {
+			return Job{}, fmt.Errorf("error parsing %s: %s", ts.name, err)
+		}
+
+		*ts.t = t
+	}
+
+	return job, nil
+}
+
+// timestampLayouts are the formats Notify uses for timestamps. Timestamps
+// without an offset are in UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999",
+	"2006-01-02 15:04:05.999999-07:00",
+	"2006-01-02 15:04:05.999999",
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown timestamp format %q", s)
+}
+
+// ErrJobStalled is returned by JobTracker when no notification of a job has
+// been found or finished for StallTimeout.
+var ErrJobStalled = errors.New("job stalled")
+
+// notificationsPageSize is the number of notifications Notify returns per
+// page of /v2/notifications.
+const notificationsPageSize = 250
+
+// JobProgress counts the notifications of a job by status.
+type JobProgress struct {
+	Job    Job
+	Counts map[NotificationStatus]int
+
+	// Notifications found so far, and how many of them are at a terminal or
+	// failure status
+	Found    int
+	Finished int
+	Failed   int
+
+	// The job is finished and every one of its notifications was found at a
+	// terminal status, or the job was cancelled or ended with an error
+	Done bool
+}
+
+// JobTracker follows the notifications of a bulk job. Notifications are
+// matched on their job ID when Notify sets one. Notify's API documentation
+// doesn't list a job ID on notifications, so otherwise they are matched on
+// the template and version of the job, created since the job was. One-off
+// sends of that template made while the job runs are then counted too, so
+// use a template only the job sends for exact counts.
+//
+// API keys can't read jobs, so the status of the job is the one returned by
+// SendBulk until every one of its notifications has been created, when it's
+// set to finished. A job that stops early, for example over its sending
+// limits, never gets there and ends with ErrJobStalled after StallTimeout.
+//
+// Every poll reads /v2/notifications from the newest notification back to
+// the creation of the job, which is a request per 250 notifications up to
+// MaxPages. These count against the rate limit of the API key like any
+// other request, so set a longer Interval, or a Client.RateLimiter, for
+// large jobs.
+type JobTracker struct {
+	Client Client
+	Job    Job
+
+	// Optional, delay between polls, defaults to 5s
+	Interval time.Duration
+
+	// Optional, most pages read per poll, defaults to enough for the job and
+	// a page of newer notifications
+	MaxPages int
+
+	// Optional, give up when no notification is found or finished for this
+	// long, defaults to 10 minutes
+	StallTimeout time.Duration
+}
+
+// NewJobTracker returns a tracker for the job of a bulk response.
+func NewJobTracker(c Client, r BulkResponse) (JobTracker, error) {
+	job, err := r.Job()
+
+	if err != nil {
+		return JobTracker{}, err
+	}
+
+	if job.TemplateId == "" || job.CreatedAt.IsZero() {
+		return JobTracker{}, fmt.Errorf("job %q has no template or created_at", job.Id)
+	}
+
+	return JobTracker{Client: c, Job: job}, nil
+}
+
+// Progress returns an iterator over the progress of the job, polled every
+// Interval until it is done. Iteration ends after the first error, including
+// when ctx ends or the job stalls.
+func (t JobTracker) Progress(ctx context.Context) iter.Seq2[JobProgress, error] {
+	return func(yield func(JobProgress, error) bool) {
+		interval := t.Interval
+
+		if interval <= 0 {
+			interval = 5 * time.Second
+		}
+
+		stallTimeout := t.StallTimeout
+
+		if stallTimeout <= 0 {
+			stallTimeout = 10 * time.Minute
+		}
+
+		var last JobProgress
+
+		lastChange := time.Now()
+
+		for {
+			progress, err := t.poll(ctx)
+			t.Job = progress.Job
+
+			if err == nil && !progress.Done {
+				if progress.Found != last.Found || progress.Finished != last.Finished {
+					last, lastChange = progress, time.Now()
+				} else if time.Since(lastChange) >= stallTimeout {
+					err = fmt.Errorf("%w: %d of %d notifications found and unchanged for %s", ErrJobStalled, progress.Found, t.Job.NotificationCount, stallTimeout)
+				}
+			}
+
+			if !yield(progress, err) || err != nil || progress.Done {
+				return
+			}
+
+			timer := time.NewTimer(interval)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				yield(progress, ctx.Err())
+				return
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// Wait polls the job until it is done and returns its final progress.
+func (t JobTracker) Wait(ctx context.Context) (JobProgress, error) {
+	var last JobProgress
+
+	for progress, err := range t.Progress(ctx) {
+		if err != nil {
+			return progress, err
+		}
+
+		last = progress
+	}
+
+	return last, nil
+}
+
+// matches reports whether n is a notification of the job.
+func (t JobTracker) matches(n StatusResponse) bool {
+	if n.JobId != "" {
+		return n.JobId == t.Job.Id
+	}
+
+	return n.Template.Id == t.Job.TemplateId && (t.Job.TemplateVersion == 0 || n.Template.Version == t.Job.TemplateVersion)
+}
+
+// poll counts the notifications of the job, stopping once all of them have
+// been seen.
+func (t JobTracker) poll(ctx context.Context) (JobProgress, error) {
+	progress := JobProgress{
+		Job:    t.Job,
+		Counts: map[NotificationStatus]int{},
+	}
+
+	switch t.Job.Status {
+	case JobStatusCancelled, JobStatusError, JobStatusSendingLimitsExceeded:
+		progress.Done = true
+		return progress, nil
+	}
+
+	maxPages := t.MaxPages
+
+	if maxPages <= 0 {
+		maxPages = t.Job.NotificationCount/notificationsPageSize + 2
+	}
+
+	pager := Pager{Client: t.Client, Options: StatusQueryOptions{IncludeJobs: true}, MaxPages: maxPages}
+
+	for n, err := range pager.All(ctx) {
+		if err != nil {
+			return progress, err
+		}
+
+		// Notifications are listed newest first
+		if n.CreatedAt.Before(t.Job.CreatedAt) {
+			break
+		}
+
+		if !t.matches(n) {
+			continue
+		}
+
+		progress.Counts[n.Status]++
+		progress.Found++
+
+		if n.Status.IsTerminal() {
+			progress.Finished++
+		}
+
+		if n.Status.IsFailure() {
+			progress.Failed++
+		}
+
+		if progress.Found >= t.Job.NotificationCount {
+			break
+		}
+	}
+
+	if progress.Found >= t.Job.NotificationCount {
+		progress.Job.Status = JobStatusFinished
+	}
+
+	progress.Done = progress.Job.Status == JobStatusFinished && progress.Finished == progress.Found
+
+	return progress, nil
+}
@@ -0,0 +1,280 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+const jobResponseBody = `{"data": {
+	"id": "11111111-1111-1111-1111-111111111111",
+	"created_at": "2024-01-01T12:00:00.000000+00:00",
+	"processing_started": "2024-01-01T12:00:01.5",
+	"processing_finished": null,
+	"job_status": "in progress",
+	"notification_count": 2,
+	"template": "00000000-0000-0000-0000-000000000000",
+	"template_version": 3
+}}`
+
+func TestBulkResponseJob(t *testing.T) {
+	t.Parallel()
+
+	var r BulkResponse
+
+	if err := json.Unmarshal([]byte(jobResponseBody), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := r.Job()
+
+	if err != nil {
+		t.Fatalf("Job returned an error: %s", err)
+	}
+
+	if !job.CreatedAt.Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected created_at %s", job.CreatedAt)
+	}
+
+	if !job.ProcessingStarted.Equal(time.Date(2024, 1, 1, 12, 0, 1, 500000000, time.UTC)) {
+		t.Errorf("Unexpected processing_started %s", job.ProcessingStarted)
+	}
+
+	if !job.ProcessingFinished.IsZero() || job.Status != JobStatusInProgress || job.NotificationCount != 2 {
+		t.Errorf("Unexpected job %+v", job)
+	}
+
+	r.Data.CreatedAt = "yesterday"
+
+	if _, err := r.Job(); err == nil {
+		t.Errorf("Expected an error for an invalid timestamp")
+	}
+}
+
+func TestJobTracker(t *testing.T) {
+	t.Parallel()
+
+	var polls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("include_jobs") != "true" {
+			t.Errorf("Expected include_jobs=true, got %s", r.URL.RawQuery)
+		}
+
+		// The second page is only needed while some of the job is missing
+		if r.URL.Query().Get("older_than") != "" {
+			if polls.Load() > 1 {
+				t.Errorf("Expected paging to stop once the job was found")
+			}
+
+			w.Write([]byte(`{"notifications": [], "links": {}}`))
+			return
+		}
+
+		notification := func(job string, status string, createdAt string) string {
+			return fmt.Sprintf(`{"status": %q, "job_id": %q, "template": {"id": "00000000-0000-0000-0000-000000000000", "version": 3}, "created_at": %q}`, status, job, createdAt)
+		}
+
+		const job = "11111111-1111-1111-1111-111111111111"
+
+		// Other jobs and sends of other templates aren't counted
+		notifications := []string{
+			notification("22222222-2222-2222-2222-222222222222", "sending", "2024-01-01T12:00:05Z"),
+			`{"status": "sending", "template": {"id": "33333333-3333-3333-3333-333333333333", "version": 1}, "created_at": "2024-01-01T12:00:04Z"}`,
+		}
+
+		switch polls.Add(1) {
+		case 1:
+			notifications = append(notifications, notification(job, "permanent-failure", "2024-01-01T12:00:02Z"))
+		case 2:
+			notifications = append(notifications, notification(job, "sending", "2024-01-01T12:00:03Z"), notification(job, "permanent-failure", "2024-01-01T12:00:02Z"))
+		default:
+			notifications = append(notifications, notification(job, "delivered", "2024-01-01T12:00:03Z"), notification(job, "permanent-failure", "2024-01-01T12:00:02Z"))
+		}
+
+		fmt.Fprintf(w, `{"notifications": [%s], "links": {"next": "/v2/notifications?include_jobs=true&older_than=x"}}`, strings.Join(notifications, ", "))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	var r BulkResponse
+	json.Unmarshal([]byte(jobResponseBody), &r)
+
+	tracker, err := NewJobTracker(c, r)
+
+	if err != nil {
+		t.Fatalf("NewJobTracker returned an error: %s", err)
+	}
+
+	tracker.Interval = time.Millisecond
+
+	var events []JobProgress
+
+	for progress, err := range tracker.Progress(context.Background()) {
+		if err != nil {
+			t.Fatalf("Progress returned an error: %s", err)
+		}
+
+		events = append(events, progress)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 progress events, got %d", len(events))
+	}
+
+	if first := events[0]; first.Done || first.Found != 1 || first.Job.Status != JobStatusInProgress {
+		t.Errorf("Unexpected first progress %+v", first)
+	}
+
+	if second := events[1]; second.Done || second.Found != 2 || second.Counts[StatusSending] != 1 || second.Job.Status != JobStatusFinished {
+		t.Errorf("Unexpected second progress %+v", second)
+	}
+
+	if last := events[2]; !last.Done || last.Counts[StatusDelivered] != 1 || last.Failed != 1 || last.Finished != 2 {
+		t.Errorf("Unexpected last progress %+v", last)
+	}
+}
+
+func TestJobTrackerMatchesTemplateWithoutJobId(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Without a job ID, notifications of the job's template and version
+		// created since the job are counted
+		w.Write([]byte(`{"notifications": [
+			{"status": "delivered", "template": {"id": "00000000-0000-0000-0000-000000000000", "version": 2}, "created_at": "2024-01-01T12:00:04Z"},
+			{"status": "delivered", "template": {"id": "00000000-0000-0000-0000-000000000000", "version": 3}, "created_at": "2024-01-01T12:00:03Z"},
+			{"status": "sent", "template": {"id": "00000000-0000-0000-0000-000000000000", "version": 3}, "created_at": "2024-01-01T12:00:02Z"},
+			{"status": "sending", "template": {"id": "00000000-0000-0000-0000-000000000000", "version": 3}, "created_at": "2024-01-01T11:59:59Z"}
+		], "links": {}}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	var r BulkResponse
+	json.Unmarshal([]byte(jobResponseBody), &r)
+
+	tracker, _ := NewJobTracker(c, r)
+	tracker.Interval = time.Millisecond
+
+	// The sent notification isn't terminal, so the first poll is enough
+	for progress, err := range tracker.Progress(context.Background()) {
+		if err != nil {
+			t.Fatalf("Progress returned an error: %s", err)
+		}
+
+		if progress.Found != 2 || progress.Finished != 1 || progress.Job.Status != JobStatusFinished || progress.Done {
+			t.Errorf("Unexpected progress %+v", progress)
+		}
+
+		break
+	}
+}
+
+func TestJobTrackerStalls(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		// A busy service, with newer notifications of another template
+		w.Write([]byte(`{"notifications": [
+			{"status": "sending", "template": {"id": "33333333-3333-3333-3333-333333333333", "version": 1}, "created_at": "2024-01-01T13:00:00Z"}
+		], "links": {"next": "/v2/notifications?include_jobs=true&older_than=x"}}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	var r BulkResponse
+	json.Unmarshal([]byte(jobResponseBody), &r)
+
+	tracker, _ := NewJobTracker(c, r)
+	tracker.Interval = time.Millisecond
+	tracker.MaxPages = 3
+	tracker.StallTimeout = 20 * time.Millisecond
+
+	progress, err := tracker.Wait(context.Background())
+
+	if !errors.Is(err, ErrJobStalled) {
+		t.Errorf("Expected ErrJobStalled, got %v", err)
+	}
+
+	if progress.Done || progress.Found != 0 {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+
+	if n := requests.Load(); n == 0 || n%3 != 0 {
+		t.Errorf("Expected 3 pages to be read per poll, got %d requests", n)
+	}
+}
+
+func TestJobTrackerEndedJob(t *testing.T) {
+	t.Parallel()
+
+	var r BulkResponse
+	json.Unmarshal([]byte(jobResponseBody), &r)
+	r.Data.JobStatus = string(JobStatusSendingLimitsExceeded)
+
+	c, _ := NewClient("test")
+	c.Hostname = "http://127.0.0.1:0"
+
+	tracker, _ := NewJobTracker(c, r)
+
+	progress, err := tracker.Wait(context.Background())
+
+	if err != nil || !progress.Done {
+		t.Errorf("Expected a job over its sending limits to be done without polling, got %+v, %v", progress, err)
+	}
+}
+
+func TestJobTrackerWaitEndsWithContext(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"notifications": [], "links": {}}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	var r BulkResponse
+	json.Unmarshal([]byte(jobResponseBody), &r)
+
+	tracker, _ := NewJobTracker(c, r)
+	tracker.Interval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	progress, err := tracker.Wait(ctx)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if progress.Done || progress.Found != 0 {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+}
@@ -0,0 +1,377 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// LimitStore keeps the number of notifications sent per channel per UTC day,
+// where day is formatted as 2006-01-02.
+type LimitStore interface {
+	// Add adds n, which can be negative, and returns the new count
+	Add(ch Channel, day string, n int) (int, error)
+	Get(ch Channel, day string) (int, error)
+}
+
+// MemoryLimitStore is a LimitStore for a single process. The zero value is
+// ready to use.
+type MemoryLimitStore struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (s *MemoryLimitStore) Add(ch Channel, day string, n int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.counts == nil {
+		s.counts = map[string]int{}
+	}
+
+	s.counts[day+"/"+string(ch)] += n
+
+	return s.counts[day+"/"+string(ch)], nil
+}
+
+func (s *MemoryLimitStore) Get(ch Channel, day string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.counts[day+"/"+string(ch)], nil
+}
+
+// FileLimitStore is a LimitStore that keeps counts in a JSON file so they
+// survive restarts. Only today's counts are kept. It isn't safe to share the
+// file between processes.
+type FileLimitStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *FileLimitStore) Add(ch Channel, day string, n int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days, err := s.read()
+
+	if err != nil {
+		return 0, err
+	}
+
+	counts := days[day]
+
+	if counts == nil {
+		counts = map[Channel]int{}
+	}
+
+	counts[ch] += n
+
+	// Drop previous days so the file doesn't grow
+	days = map[string]map[Channel]int{day: counts}
+
+	body, err := json.Marshal(days)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.WriteFile(s.Path, body, 0o600); err != nil {
+		return 0, fmt.Errorf("error writing limit store: %w", err)
+	}
+
+	return counts[ch], nil
+}
+
+func (s *FileLimitStore) Get(ch Channel, day string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days, err := s.read()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return days[day][ch], nil
+}
+
+func (s *FileLimitStore) read() (map[string]map[Channel]int, error) {
+	days := map[string]map[Channel]int{}
+
+	body, err := os.ReadFile(s.Path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return days, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading limit store: %w", err)
+	}
+
+	if err := json.Unmarshal(body, &days); err != nil {
+		return nil, fmt.Errorf("error decoding limit store: %w", err)
+	}
+
+	return days, nil
+}
+
+// LimitTracker counts notifications sent per channel per UTC day, the same
+// way Notify enforces daily limits, so sends that would go over a limit can
+// be refused before calling the API. It's safe for concurrent use.
+type LimitTracker struct {
+	// Daily limit per channel, channels without a limit aren't tracked
+	Limits map[Channel]int
+
+	// Optional, defaults to a MemoryLimitStore
+	Store LimitStore
+
+	// Optional, wait for the limit to reset at midnight UTC instead of
+	// returning ErrDailyLimitExceeded
+	Defer bool
+
+	// Optional, defaults to time.Now
+	Now func() time.Time
+
+	mu    sync.Mutex
+	store LimitStore
+}
+
+func (t *LimitTracker) now() time.Time {
+	if t.Now != nil {
+		return t.Now().UTC()
+	}
+
+	return time.Now().UTC()
+}
+
+func (t *LimitTracker) getStore() LimitStore {
+	if t.Store != nil {
+		return t.Store
+	}
+
+	if t.store == nil {
+		t.store = &MemoryLimitStore{}
+	}
+
+	return t.store
+}
+
+func day(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// Seed sets how many notifications were already sent today on ch, for
+// example sends made before the process started.
+func (t *LimitTracker) Seed(ch Channel, sent int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	today := day(t.now())
+
+	count, err := t.getStore().Get(ch, today)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = t.getStore().Add(ch, today, sent-count)
+
+	return err
+}
+
+// Remaining returns how many more notifications can be sent today on ch, or
+// -1 if ch has no limit.
+func (t *LimitTracker) Remaining(ch Channel) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	limit, ok := t.Limits[ch]
+
+	if !ok {
+		return -1, nil
+	}
+
+	count, err := t.getStore().Get(ch, day(t.now()))
+
+	if err != nil {
+		return 0, err
+	}
+
+	return max(limit-count, 0), nil
+}
+
+// Reserve counts n notifications on ch against today's limit. If they don't
+// fit it returns an error matching ErrDailyLimitExceeded, or waits for the
+// next day when Defer is set.
+func (t *LimitTracker) Reserve(ctx context.Context, ch Channel, n int) error {
+	_, err := t.reserve(ctx, ch, n)
+
+	return err
+}
+
+// reserve is Reserve returning the day n was counted against, empty when ch
+// has no limit.
+func (t *LimitTracker) reserve(ctx context.Context, ch Channel, n int) (string, error) {
+	limit, ok := t.Limits[ch]
+
+	if !ok {
+		return "", nil
+	}
+
+	if n > limit {
+		return "", fmt.Errorf("%w: %d %s notifications is more than the daily limit of %d", ErrDailyLimitExceeded, n, ch, limit)
+	}
+
+	for {
+		now := t.now()
+
+		ok, err := t.tryReserve(ch, day(now), n, limit)
+
+		if err != nil || ok {
+			return day(now), err
+		}
+
+		if !t.Defer {
+			return "", fmt.Errorf("%w: sending %d %s notifications would go over the daily limit of %d", ErrDailyLimitExceeded, n, ch, limit)
+		}
+
+		if err := waitForNextDay(ctx, now); err != nil {
+			return "", err
+		}
+	}
+}
+
+// reserveRemaining reserves what is left of today's limit on ch, for sends
+// whose size isn't known until they are made. When nothing is left it returns
+// an error matching ErrDailyLimitExceeded, or waits for the next day when
+// Defer is set. It returns the day of the reservation and its size, which is
+// -1 when ch has no limit.
+func (t *LimitTracker) reserveRemaining(ctx context.Context, ch Channel) (string, int, error) {
+	limit, ok := t.Limits[ch]
+
+	if !ok {
+		return "", -1, nil
+	}
+
+	for {
+		now := t.now()
+
+		n, err := t.tryReserveRemaining(ch, day(now), limit)
+
+		if err != nil || n > 0 {
+			return day(now), n, err
+		}
+
+		if !t.Defer {
+			return "", 0, fmt.Errorf("%w: no %s notifications left of the daily limit of %d", ErrDailyLimitExceeded, ch, limit)
+		}
+
+		if err := waitForNextDay(ctx, now); err != nil {
+			return "", 0, err
+		}
+	}
+}
+
+func (t *LimitTracker) tryReserveRemaining(ch Channel, today string, limit int) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count, err := t.getStore().Get(ch, today)
+
+	if err != nil || count >= limit {
+		return 0, err
+	}
+
+	if _, err := t.getStore().Add(ch, today, limit-count); err != nil {
+		return 0, err
+	}
+
+	return limit - count, nil
+}
+
+// waitForNextDay waits until midnight UTC after now, or for ctx to end.
+func waitForNextDay(ctx context.Context, now time.Time) error {
+	tomorrow := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	timer := time.NewTimer(tomorrow.Sub(now))
+
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (t *LimitTracker) tryReserve(ch Channel, today string, n int, limit int) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count, err := t.getStore().Get(ch, today)
+
+	if err != nil {
+		return false, err
+	}
+
+	if count+n > limit {
+		return false, nil
+	}
+
+	_, err = t.getStore().Add(ch, today, n)
+
+	return err == nil, err
+}
+
+// Release gives back n notifications reserved today on ch, when the send
+// didn't go through.
+func (t *LimitTracker) Release(ch Channel, n int) error {
+	return t.release(ch, day(t.now()), n)
+}
+
+// release gives back n notifications reserved on reservedDay. Reservations of
+// a previous day are left, as its count no longer limits anything.
+func (t *LimitTracker) release(ch Channel, reservedDay string, n int) error {
+	if _, ok := t.Limits[ch]; !ok {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if reservedDay != day(t.now()) {
+		return nil
+	}
+
+	_, err := t.getStore().Add(ch, reservedDay, -n)
+
+	return err
+}
+
+// reserveDailyLimit reserves n notifications with c.LimitTracker. The
+// returned func releases them unless the status code shows the send was
+// accepted.
+func (c Client) reserveDailyLimit(ctx context.Context, ch Channel, n int) (func(statusCode int), error) {
+	if c.LimitTracker == nil {
+		return func(int) {}, nil
+	}
+
+	reservedDay, err := c.LimitTracker.reserve(ctx, ch, n)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Released against the day of the reservation, as the response may come
+	// after midnight
+	return func(statusCode int) {
+		if statusCode < 200 || statusCode >= 300 {
+			c.LimitTracker.release(ch, reservedDay, n)
+		}
+	}, nil
+}
@@ -0,0 +1,239 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestLimitTrackerRefusesSendsOverLimit(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "00000000-0000-0000-0000-000000000000"}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.LimitTracker = &LimitTracker{
+		Limits: map[Channel]int{ChannelEmail: 2},
+	}
+
+	e := Email{
+		EmailAddress: "test@test.com",
+		TemplateId:   "00000000-0000-0000-0000-000000000000",
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.SendEmail(e); err != nil {
+			t.Errorf("Expected email %d to be sent, got %s", i+1, err)
+		}
+	}
+
+	if _, err := c.SendEmail(e); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Errorf("Expected ErrDailyLimitExceeded, got %v", err)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 requests, got %d", calls.Load())
+	}
+
+	// SMS has no limit
+	if _, err := c.SendSms(Sms{PhoneNumber: "1234567890", TemplateId: "00000000-0000-0000-0000-000000000000"}); err != nil {
+		t.Errorf("Expected sms to be sent, got %s", err)
+	}
+
+	remaining, _ := c.LimitTracker.Remaining(ChannelEmail)
+
+	if remaining != 0 {
+		t.Errorf("Expected 0 emails remaining, got %d", remaining)
+	}
+}
+
+func TestLimitTrackerReleasesFailedSends(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status_code": 400, "errors": [{"error": "BadRequestError", "message": "Template not found"}]}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.LimitTracker = &LimitTracker{
+		Limits: map[Channel]int{ChannelEmail: 10},
+	}
+
+	c.SendBulkEmail(BulkEmail{
+		Name:       "Test",
+		TemplateId: "00000000-0000-0000-0000-000000000000",
+		Csv:        "email address\ntest@test.com\n\"test2@test.com\"\n",
+	})
+
+	remaining, _ := c.LimitTracker.Remaining(ChannelEmail)
+
+	if remaining != 10 {
+		t.Errorf("Expected 10 emails remaining, got %d", remaining)
+	}
+}
+
+func TestLimitTrackerReleasesAcrossMidnight(t *testing.T) {
+	t.Parallel()
+
+	var now atomic.Int64
+
+	now.Store(time.Date(2024, 1, 1, 23, 59, 59, 0, time.UTC).Unix())
+
+	// The send fails once the next day has started
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now.Add(2)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status_code": 400, "errors": [{"error": "BadRequestError", "message": "Template not found"}]}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.LimitTracker = &LimitTracker{
+		Limits: map[Channel]int{ChannelSms: 10},
+		Now: func() time.Time {
+			return time.Unix(now.Load(), 0)
+		},
+	}
+
+	c.SendSms(Sms{PhoneNumber: "+16135550123", TemplateId: "00000000-0000-0000-0000-000000000000"})
+
+	c.LimitTracker.Reserve(context.Background(), ChannelSms, 10)
+
+	if remaining, _ := c.LimitTracker.Remaining(ChannelSms); remaining != 0 {
+		t.Errorf("Expected the release not to count against the next day, got %d remaining", remaining)
+	}
+}
+
+func TestLimitTrackerCountsBulkRows(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data": {"id": "00000000-0000-0000-0000-000000000000"}}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.LimitTracker = &LimitTracker{
+		Limits: map[Channel]int{ChannelEmail: 3},
+	}
+
+	_, err := c.SendBulkEmail(BulkEmail{
+		Name:       "Test",
+		TemplateId: "00000000-0000-0000-0000-000000000000",
+		Csv:        "email address,name\ntest@test.com,\"Test\nTest\"\ntest2@test.com,Test\n",
+	})
+
+	if err != nil {
+		t.Errorf("Expected bulk email to be sent, got %s", err)
+	}
+
+	remaining, _ := c.LimitTracker.Remaining(ChannelEmail)
+
+	if remaining != 1 {
+		t.Errorf("Expected 1 email remaining, got %d", remaining)
+	}
+
+	_, err = c.SendBulkEmail(BulkEmail{
+		Name:       "Test",
+		TemplateId: "00000000-0000-0000-0000-000000000000",
+		Rows:       [][]string{{"email address"}, {"test@test.com"}, {"test2@test.com"}},
+	})
+
+	if !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Errorf("Expected ErrDailyLimitExceeded, got %v", err)
+	}
+}
+
+func TestLimitTrackerResetsDaily(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+
+	tracker := &LimitTracker{
+		Limits: map[Channel]int{ChannelSms: 5},
+		Now: func() time.Time {
+			return now
+		},
+	}
+
+	tracker.Seed(ChannelSms, 5)
+
+	if err := tracker.Reserve(context.Background(), ChannelSms, 1); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Errorf("Expected ErrDailyLimitExceeded, got %v", err)
+	}
+
+	now = now.Add(time.Hour)
+
+	if err := tracker.Reserve(context.Background(), ChannelSms, 1); err != nil {
+		t.Errorf("Expected sms to be allowed the next day, got %s", err)
+	}
+}
+
+func TestLimitTrackerDeferWaitsForContext(t *testing.T) {
+	t.Parallel()
+
+	tracker := &LimitTracker{
+		Limits: map[Channel]int{ChannelSms: 1},
+		Defer:  true,
+	}
+
+	tracker.Reserve(context.Background(), ChannelSms, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := tracker.Reserve(ctx, ChannelSms, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestFileLimitStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "limits.json")
+
+	store := &FileLimitStore{Path: path}
+
+	store.Add(ChannelEmail, "2024-01-01", 3)
+	store.Add(ChannelEmail, "2024-01-02", 2)
+
+	// A new store reads the same file
+	store = &FileLimitStore{Path: path}
+
+	got, err := store.Get(ChannelEmail, "2024-01-02")
+
+	if err != nil || got != 2 {
+		t.Errorf("Expected 2 emails on 2024-01-02, got %d %v", got, err)
+	}
+
+	got, _ = store.Get(ChannelEmail, "2024-01-01")
+
+	if got != 0 {
+		t.Errorf("Expected previous days to be dropped, got %d", got)
+	}
+}
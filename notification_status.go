@@ -0,0 +1,171 @@
+package client
+
+import (
+	"fmt"
+	"slices"
+)
+
+type NotificationStatus string
+
+const (
+	StatusCreated           NotificationStatus = "created"
+	StatusPendingVirusCheck NotificationStatus = "pending-virus-check"
+	StatusSending           NotificationStatus = "sending"
+	StatusPending           NotificationStatus = "pending"
+	StatusSent              NotificationStatus = "sent"
+	StatusDelivered         NotificationStatus = "delivered"
+	StatusPermanentFailure  NotificationStatus = "permanent-failure"
+	StatusTemporaryFailure  NotificationStatus = "temporary-failure"
+	StatusTechnicalFailure  NotificationStatus = "technical-failure"
+	StatusVirusScanFailed   NotificationStatus = "virus-scan-failed"
+	StatusValidationFailed  NotificationStatus = "validation-failed"
+	StatusPIICheckFailed    NotificationStatus = "pii-check-failed"
+	StatusCancelled         NotificationStatus = "cancelled"
+
+	// Letters only
+	StatusAccepted       NotificationStatus = "accepted"
+	StatusReceived       NotificationStatus = "received"
+	StatusReturnedLetter NotificationStatus = "returned-letter"
+
+	// Only used in StatusQueryOptions, matches every failure status
+	StatusFailed NotificationStatus = "failed"
+)
+
+// transitions lists the statuses a notification can move to from each
+// status. A status can always be observed again.
+var transitions = map[NotificationStatus][]NotificationStatus{
+	StatusCreated: {
+		StatusPendingVirusCheck, StatusSending, StatusTechnicalFailure,
+		StatusValidationFailed, StatusPIICheckFailed, StatusCancelled, StatusAccepted,
+	},
+	StatusPendingVirusCheck: {
+		StatusCreated, StatusSending, StatusVirusScanFailed, StatusValidationFailed,
+		StatusTechnicalFailure,
+	},
+	StatusSending: {
+		StatusPending, StatusSent, StatusDelivered, StatusPermanentFailure,
+		StatusTemporaryFailure, StatusTechnicalFailure,
+	},
+	StatusPending: {
+		StatusDelivered, StatusPermanentFailure, StatusTemporaryFailure, StatusTechnicalFailure,
+	},
+	// International SMS may never leave sent
+	StatusSent: {
+		StatusDelivered, StatusPermanentFailure, StatusTemporaryFailure,
+	},
+	// Emails can bounce after being delivered
+	StatusDelivered: {
+		StatusPermanentFailure,
+	},
+	StatusAccepted: {
+		StatusReceived, StatusCancelled, StatusTechnicalFailure,
+	},
+	StatusReceived: {
+		StatusReturnedLetter,
+	},
+}
+
+// Sent isn't terminal as delivery receipts can still arrive
+var terminalStatuses = []NotificationStatus{
+	StatusDelivered, StatusPermanentFailure, StatusTemporaryFailure,
+	StatusTechnicalFailure, StatusVirusScanFailed, StatusValidationFailed,
+	StatusPIICheckFailed, StatusCancelled, StatusReceived, StatusReturnedLetter,
+}
+
+var failureStatuses = []NotificationStatus{
+	StatusPermanentFailure, StatusTemporaryFailure, StatusTechnicalFailure,
+	StatusVirusScanFailed, StatusValidationFailed, StatusPIICheckFailed,
+	StatusReturnedLetter, StatusFailed,
+}
+
+// Failures that won't succeed if the notification is sent again
+var permanentFailureStatuses = []NotificationStatus{
+	StatusPermanentFailure, StatusVirusScanFailed, StatusValidationFailed,
+	StatusPIICheckFailed, StatusReturnedLetter,
+}
+
+// IsValid reports whether s is a status known to Notify.
+func (s NotificationStatus) IsValid() bool {
+	_, ok := transitions[s]
+
+	return ok || slices.Contains(terminalStatuses, s) || s == StatusFailed
+}
+
+// IsTerminal reports whether Notify has finished processing the
+// notification. Emails that are delivered can still bounce later, and letters
+// that are received can still be returned.
+func (s NotificationStatus) IsTerminal() bool {
+	return slices.Contains(terminalStatuses, s)
+}
+
+func (s NotificationStatus) IsFailure() bool {
+	return slices.Contains(failureStatuses, s)
+}
+
+// IsPermanent reports whether s is a failure that sending the notification
+// again won't fix.
+func (s NotificationStatus) IsPermanent() bool {
+	return slices.Contains(permanentFailureStatuses, s)
+}
+
+// NextStates returns the statuses a notification can move to from s.
+func (s NotificationStatus) NextStates() []NotificationStatus {
+	return slices.Clone(transitions[s])
+}
+
+// CanTransitionTo reports whether a notification can move from s to next.
+func (s NotificationStatus) CanTransitionTo(next NotificationStatus) bool {
+	return s == next || slices.Contains(transitions[s], next)
+}
+
+// canReach reports whether a notification can get from s to next through
+// any number of transitions.
+func (s NotificationStatus) canReach(next NotificationStatus) bool {
+	seen := map[NotificationStatus]bool{s: true}
+	queue := []NotificationStatus{s}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == next {
+			return true
+		}
+
+		for _, n := range transitions[current] {
+			if !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	return false
+}
+
+// ValidateTimeline returns an error for the first status in timeline that
+// can't follow the one before it. Polling can miss statuses, so a status
+// only has to be reachable from the one before it.
+func ValidateTimeline(timeline []StatusResponse) error {
+	for i := 1; i < len(timeline); i++ {
+		from, to := timeline[i-1].Status, timeline[i].Status
+
+		if !from.canReach(to) {
+			return fmt.Errorf("impossible status transition from %s to %s", from, to)
+		}
+	}
+
+	return nil
+}
+
+type TemplateType string
+
+const (
+	TemplateTypeEmail  TemplateType = "email"
+	TemplateTypeSms    TemplateType = "sms"
+	TemplateTypeLetter TemplateType = "letter"
+)
+
+func (t TemplateType) IsValid() bool {
+	return t == TemplateTypeEmail || t == TemplateTypeSms || t == TemplateTypeLetter
+}
@@ -0,0 +1,136 @@
+package client_test
+
+import (
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestNotificationStatusHelpers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status    NotificationStatus
+		valid     bool
+		terminal  bool
+		failure   bool
+		permanent bool
+	}{
+		{StatusCreated, true, false, false, false},
+		{StatusSending, true, false, false, false},
+		{StatusSent, true, false, false, false},
+		{StatusDelivered, true, true, false, false},
+		{StatusPermanentFailure, true, true, true, true},
+		{StatusTemporaryFailure, true, true, true, false},
+		{StatusTechnicalFailure, true, true, true, false},
+		{StatusFailed, true, false, true, false},
+		{"delivred", false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsValid(); got != tt.valid {
+				t.Errorf("IsValid() = %v, want %v", got, tt.valid)
+			}
+
+			if got := tt.status.IsTerminal(); got != tt.terminal {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.terminal)
+			}
+
+			if got := tt.status.IsFailure(); got != tt.failure {
+				t.Errorf("IsFailure() = %v, want %v", got, tt.failure)
+			}
+
+			if got := tt.status.IsPermanent(); got != tt.permanent {
+				t.Errorf("IsPermanent() = %v, want %v", got, tt.permanent)
+			}
+		})
+	}
+}
+
+func TestTerminalStatusesDontMove(t *testing.T) {
+	t.Parallel()
+
+	statuses := []NotificationStatus{
+		StatusCreated, StatusPendingVirusCheck, StatusSending, StatusPending, StatusSent,
+		StatusPermanentFailure, StatusTemporaryFailure, StatusTechnicalFailure,
+		StatusVirusScanFailed, StatusValidationFailed, StatusPIICheckFailed, StatusCancelled,
+		StatusAccepted, StatusReturnedLetter,
+	}
+
+	// Delivered and received are terminal but can still bounce or be returned
+	for _, s := range statuses {
+		if s.IsTerminal() && len(s.NextStates()) > 0 {
+			t.Errorf("Expected terminal status %s to have no next states, got %v", s, s.NextStates())
+		}
+
+		if !s.IsTerminal() && len(s.NextStates()) == 0 {
+			t.Errorf("Expected status %s without next states to be terminal", s)
+		}
+	}
+}
+
+func TestNotificationStatusTransitions(t *testing.T) {
+	t.Parallel()
+
+	if !StatusSending.CanTransitionTo(StatusDelivered) {
+		t.Errorf("Expected sending to be able to move to delivered")
+	}
+
+	if !StatusSending.CanTransitionTo(StatusSending) {
+		t.Errorf("Expected sending to be able to be observed again")
+	}
+
+	if StatusTechnicalFailure.CanTransitionTo(StatusDelivered) {
+		t.Errorf("Expected technical-failure not to be able to move to delivered")
+	}
+
+	// NextStates returns a copy
+	next := StatusPending.NextStates()
+	next[0] = StatusCreated
+
+	if StatusPending.CanTransitionTo(StatusCreated) {
+		t.Errorf("Expected NextStates() not to modify the state machine")
+	}
+}
+
+func TestValidateTimeline(t *testing.T) {
+	t.Parallel()
+
+	// Statuses can be missed between polls
+	valid := []StatusResponse{
+		{Status: StatusCreated},
+		{Status: StatusDelivered},
+		{Status: StatusPermanentFailure},
+	}
+
+	if err := ValidateTimeline(valid); err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+
+	invalid := []StatusResponse{
+		{Status: StatusCreated},
+		{Status: StatusTechnicalFailure},
+		{Status: StatusDelivered},
+	}
+
+	err := ValidateTimeline(invalid)
+
+	if err == nil || err.Error() != "impossible status transition from technical-failure to delivered" {
+		t.Errorf("Expected impossible transition error, got %v", err)
+	}
+}
+
+func TestGetStatusWithInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewClient("test")
+
+	if _, err := c.GetStatus(StatusQueryOptions{Status: "delivred"}); err == nil || err.Error() != `invalid status "delivred"` {
+		t.Errorf("Expected invalid status error, got %v", err)
+	}
+
+	if _, err := c.GetStatus(StatusQueryOptions{TemplateType: "emial"}); err == nil || err.Error() != `invalid template type "emial"` {
+		t.Errorf("Expected invalid template type error, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"iter"
+)
+
+// Pager walks every page of notification statuses matching Options, newest
+// first.
+type Pager struct {
+	Client  Client
+	Options StatusQueryOptions
+
+	// Optional, stop after this many notifications or pages
+	MaxItems int
+	MaxPages int
+
+	// Optional, fetch the next page while the current one is being consumed
+	Prefetch bool
+}
+
+// Statuses returns an iterator over every notification status matching
+// options.
+func (c Client) Statuses(ctx context.Context, options StatusQueryOptions) iter.Seq2[StatusResponse, error] {
+	return Pager{Client: c, Options: options}.All(ctx)
+}
+
+// All returns an iterator over the notifications of every page. Iteration
+// ends after the first error.
+func (p Pager) All(ctx context.Context) iter.Seq2[StatusResponse, error] {
+	return func(yield func(StatusResponse, error) bool) {
+		items := 0
+
+		for page, err := range p.Pages(ctx) {
+			if err != nil {
+				yield(StatusResponse{}, err)
+				return
+			}
+
+			for _, notification := range page.Notifications {
+				if !yield(notification, nil) {
+					return
+				}
+
+				items++
+
+				if p.MaxItems > 0 && items >= p.MaxItems {
+					return
+				}
+			}
+		}
+	}
+}
+
+type statusPageResult struct {
+	page StatusResponses
+	err  error
+}
+
+// Pages returns an iterator over each page of notifications. A page with a
+// non-2xx status code is returned as an *APIError. Iteration ends after the
+// first error.
+func (p Pager) Pages(ctx context.Context) iter.Seq2[StatusResponses, error] {
+	return func(yield func(StatusResponses, error) bool) {
+		// Stops any prefetch in flight when the caller stops iterating
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		page, err := p.Client.GetStatusContext(ctx, p.Options)
+
+		for pages := 1; ; pages++ {
+			if err == nil {
+				err = statusPageError(page)
+			}
+
+			if err != nil {
+				yield(page, err)
+				return
+			}
+
+			more := page.HasNext() && (p.MaxPages <= 0 || pages < p.MaxPages)
+
+			var next chan statusPageResult
+
+			if more && p.Prefetch {
+				next = make(chan statusPageResult, 1)
+
+				go func(page StatusResponses) {
+					page, err := p.Client.NextStatusPageContext(ctx, page)
+					next <- statusPageResult{page, err}
+				}(page)
+			}
+
+			if !yield(page, nil) || !more {
+				return
+			}
+
+			if next != nil {
+				result := <-next
+				page, err = result.page, result.err
+			} else {
+				page, err = p.Client.NextStatusPageContext(ctx, page)
+			}
+		}
+	}
+}
+
+func statusPageError(page StatusResponses) error {
+	if page.StatusCode >= 200 && page.StatusCode < 300 {
+		return nil
+	}
+
+	return &APIError{StatusCode: page.StatusCode, Errors: page.Errors}
+}
@@ -0,0 +1,184 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+// newPagedServer serves pages of 2 notifications with ids 1 to 6, linking to
+// the next page with an absolute URL.
+func newPagedServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		// Verify the request URL
+		if r.URL.Path != "/v2/notifications" {
+			t.Errorf("Expected request to /v2/notifications, got %s", r.URL.Path)
+		}
+
+		if r.URL.Query().Get("template_type") != "email" {
+			t.Errorf("Expected template_type to be email, got %s", r.URL.RawQuery)
+		}
+
+		page := 0
+		fmt.Sscanf(r.URL.Query().Get("older_than"), "%d", &page)
+
+		response := StatusResponses{
+			Notifications: []StatusResponse{
+				{Id: fmt.Sprint(page + 1)},
+				{Id: fmt.Sprint(page + 2)},
+			},
+		}
+
+		if page < 4 {
+			response.Links.Next = fmt.Sprintf("%s/v2/notifications?template_type=email&older_than=%d", server.URL, page+2)
+		}
+
+		json.NewEncoder(w).Encode(response)
+	}))
+
+	return server
+}
+
+func collectIds(t *testing.T, seq func(func(StatusResponse, error) bool)) []string {
+	var ids []string
+
+	for s, err := range seq {
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		ids = append(ids, s.Id)
+	}
+
+	return ids
+}
+
+func TestStatusesIteratesAllPages(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	server := newPagedServer(t, &requests)
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL + "/"
+
+	ids := collectIds(t, c.Statuses(context.Background(), StatusQueryOptions{TemplateType: "email"}))
+
+	if fmt.Sprint(ids) != "[1 2 3 4 5 6]" {
+		t.Errorf("Expected ids 1 to 6, got %v", ids)
+	}
+
+	if requests.Load() != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests.Load())
+	}
+}
+
+func TestPagerLimits(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	server := newPagedServer(t, &requests)
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	p := Pager{
+		Client:   c,
+		Options:  StatusQueryOptions{TemplateType: "email"},
+		MaxItems: 3,
+	}
+
+	ids := collectIds(t, p.All(context.Background()))
+
+	if fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("Expected ids 1 to 3, got %v", ids)
+	}
+
+	p.MaxItems = 0
+	p.MaxPages = 1
+
+	ids = collectIds(t, p.All(context.Background()))
+
+	if fmt.Sprint(ids) != "[1 2]" {
+		t.Errorf("Expected ids 1 to 2, got %v", ids)
+	}
+}
+
+func TestPagerPrefetch(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	server := newPagedServer(t, &requests)
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	p := Pager{
+		Client:   c,
+		Options:  StatusQueryOptions{TemplateType: "email"},
+		Prefetch: true,
+	}
+
+	pages := 0
+
+	for page, err := range p.Pages(context.Background()) {
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		pages++
+
+		// The next page is requested before this one is consumed
+		if page.HasNext() && int(requests.Load()) < pages {
+			t.Errorf("Expected page %d to be prefetched", pages+1)
+		}
+	}
+
+	if pages != 3 {
+		t.Errorf("Expected 3 pages, got %d", pages)
+	}
+}
+
+func TestPagerStopsOnErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"status_code": 403, "errors": [{"error": "AuthError", "message": "Invalid token"}]}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	count := 0
+
+	for _, err := range c.Statuses(context.Background(), StatusQueryOptions{}) {
+		count++
+
+		if !errors.Is(err, ErrAuth) {
+			t.Errorf("Expected ErrAuth, got %v", err)
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("Expected a single error, got %d items", count)
+	}
+}
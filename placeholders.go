@@ -0,0 +1,216 @@
+package client
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The same pattern Notify uses: two brackets around anything but brackets
+var placeholderPattern = regexp.MustCompile(`\(\(([^()]+)\)\)`)
+
+// Placeholder is a ((name)) or ((name??text)) in a template. Conditional
+// placeholders show their text when the personalisation value is yes or
+// true.
+type Placeholder struct {
+	Name        string
+	Conditional bool
+	Text        string
+}
+
+// ParsePlaceholders returns every placeholder in s, in order.
+func ParsePlaceholders(s string) []Placeholder {
+	var placeholders []Placeholder
+
+	for _, match := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+		placeholders = append(placeholders, parsePlaceholder(match[1]))
+	}
+
+	return placeholders
+}
+
+func parsePlaceholder(s string) Placeholder {
+	name, text, conditional := strings.Cut(s, "??")
+
+	return Placeholder{
+		Name:        strings.TrimSpace(name),
+		Conditional: conditional,
+		Text:        text,
+	}
+}
+
+// RequiredFields returns the personalisation keys needed by the given
+// template subject and body, in the order they first appear. Keys that
+// differ only in case, spaces, dashes or underscores are the same key to
+// Notify and are only returned once.
+func RequiredFields(texts ...string) []string {
+	var fields []string
+
+	seen := map[string]bool{}
+
+	for _, text := range texts {
+		for _, p := range ParsePlaceholders(text) {
+			key := normaliseKey(p.Name)
+
+			if !seen[key] {
+				seen[key] = true
+				fields = append(fields, p.Name)
+			}
+		}
+	}
+
+	return fields
+}
+
+var keyReplacer = strings.NewReplacer(" ", "", "_", "", "-", "")
+
+// normaliseKey matches personalisation keys and column names the way Notify
+// does, ignoring case, spaces, dashes and underscores.
+func normaliseKey(key string) string {
+	return strings.ToLower(keyReplacer.Replace(key))
+}
+
+type PersonalisationReport struct {
+	// Required fields without a value
+	Missing []string
+
+	// Values that don't match a field, Notify ignores them
+	Extra []string
+
+	// Values Notify can't put in a template
+	WrongType []string
+}
+
+// Err returns an error describing missing and wrongly typed values, or nil.
+// Extra values aren't an error.
+func (r PersonalisationReport) Err() error {
+	var problems []string
+
+	if len(r.Missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing %s", strings.Join(r.Missing, ", ")))
+	}
+
+	if len(r.WrongType) > 0 {
+		problems = append(problems, fmt.Sprintf("wrong type for %s", strings.Join(r.WrongType, ", ")))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("invalid personalisation: %s", strings.Join(problems, "; "))
+}
+
+// CheckPersonalisation compares personalisation against the required fields
+// of a template.
+func CheckPersonalisation(required []string, personalisation map[string]interface{}) PersonalisationReport {
+	var report PersonalisationReport
+
+	present := map[string]bool{}
+
+	for key := range personalisation {
+		present[normaliseKey(key)] = true
+	}
+
+	fields := map[string]bool{}
+
+	for _, field := range required {
+		fields[normaliseKey(field)] = true
+
+		if !present[normaliseKey(field)] {
+			report.Missing = append(report.Missing, field)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(personalisation)) {
+		value := personalisation[key]
+
+		if !isPersonalisationValue(value) {
+			report.WrongType = append(report.WrongType, key)
+		}
+
+		// Attached files don't appear in the template
+		if !fields[normaliseKey(key)] && !isAttachedFile(value) {
+			report.Extra = append(report.Extra, key)
+		}
+	}
+
+	return report
+}
+
+// CheckPersonalisation compares the email personalisation against t.
+func (e Email) CheckPersonalisation(t Template) PersonalisationReport {
+	return CheckPersonalisation(RequiredFields(t.Subject, t.Body), e.Personalisation)
+}
+
+// CheckPersonalisation compares the sms personalisation against t.
+func (s Sms) CheckPersonalisation(t Template) PersonalisationReport {
+	personalisation := make(map[string]interface{}, len(s.Personalisation))
+
+	for k, v := range s.Personalisation {
+		personalisation[k] = v
+	}
+
+	return CheckPersonalisation(RequiredFields(t.Body), personalisation)
+}
+
+// isPersonalisationValue reports whether v is a scalar, a list of scalars
+// which Notify shows as a bulleted list, or a file.
+func isPersonalisationValue(v interface{}) bool {
+	if isScalar(v) {
+		return true
+	}
+
+	if _, ok := v.(map[string]interface{}); ok {
+		return isFile(v)
+	}
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false
+	}
+
+	for i := 0; i < rv.Len(); i++ {
+		if !isScalar(rv.Index(i).Interface()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isScalar(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+func isFile(v interface{}) bool {
+	file, ok := v.(map[string]interface{})
+
+	if !ok {
+		return false
+	}
+
+	_, hasFile := file["file"]
+
+	return hasFile
+}
+
+func isAttachedFile(v interface{}) bool {
+	return isFile(v) && v.(map[string]interface{})["sending_method"] == "attach"
+}
@@ -0,0 +1,93 @@
+package client_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestParsePlaceholders(t *testing.T) {
+	t.Parallel()
+
+	got := ParsePlaceholders("Hello ((first name)), (not one) ((())) ((reference))")
+
+	want := []Placeholder{
+		{Name: "first name"},
+		{Name: "reference"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePlaceholders() = %+v, want %+v", got, want)
+	}
+
+	got = ParsePlaceholders("((show??Your appointment is tomorrow))")
+
+	want = []Placeholder{
+		{Name: "show", Conditional: true, Text: "Your appointment is tomorrow"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePlaceholders() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRequiredFields(t *testing.T) {
+	t.Parallel()
+
+	got := RequiredFields("Hello ((First Name))", "Dear ((first_name)), ((show??Text)) ((reference-number))")
+
+	want := []string{"First Name", "show", "reference-number"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RequiredFields() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckPersonalisation(t *testing.T) {
+	t.Parallel()
+
+	template := Template{
+		Subject: "Your ((service)) application",
+		Body:    "Hello ((first name)),\n\n((items))\n\n((show link??Apply online))",
+	}
+
+	e := Email{
+		Personalisation: map[string]interface{}{
+			"First_Name": "Alex",
+			"items":      []string{"Passport", "Photo"},
+			"show link":  true,
+			"unused":     "value",
+			"nested":     map[string]interface{}{"a": "b"},
+			"attachment": map[string]interface{}{
+				"file":           "dGVzdA==",
+				"filename":       "test.pdf",
+				"sending_method": "attach",
+			},
+		},
+	}
+
+	got := e.CheckPersonalisation(template)
+
+	want := PersonalisationReport{
+		Missing:   []string{"service"},
+		Extra:     []string{"nested", "unused"},
+		WrongType: []string{"nested"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckPersonalisation() = %+v, want %+v", got, want)
+	}
+
+	if err := got.Err(); err == nil || err.Error() != "invalid personalisation: missing service; wrong type for nested" {
+		t.Errorf("Expected invalid personalisation error, got %v", err)
+	}
+
+	s := Sms{
+		Personalisation: map[string]string{"first name": "Alex", "items": "Passport", "show link": "yes"},
+	}
+
+	if err := s.CheckPersonalisation(Template{Body: template.Body}).Err(); err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+}
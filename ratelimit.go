@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Channel is what a request sends, so limits can be set separately for
+// email, SMS and letters.
+type Channel string
+
+const (
+	ChannelEmail  Channel = "email"
+	ChannelSms    Channel = "sms"
+	ChannelLetter Channel = "letter"
+)
+
+type Rate struct {
+	Requests int
+	Per      time.Duration
+}
+
+// DefaultRateLimits are the per key limits applied by Notify to API requests.
+var DefaultRateLimits = map[KeyType]Rate{
+	KeyTypeLive: {Requests: 1000, Per: time.Minute},
+	KeyTypeTeam: {Requests: 1000, Per: time.Minute},
+	KeyTypeTest: {Requests: 1000, Per: time.Minute},
+}
+
+// RateLimiter paces requests with token buckets so they stay under Notify's
+// rate limits. It's safe for concurrent use and should be shared by all
+// clients using the same API key.
+type RateLimiter struct {
+	// Applies to every request
+	Rate Rate
+
+	// Optional, additional limits for requests on a channel
+	Channels map[Channel]Rate
+
+	// Optional, return an error matching ErrRateLimited instead of waiting
+	// for the next request to be allowed. Waiting also fails fast when the
+	// context deadline would pass first.
+	FailFast bool
+
+	mu          sync.Mutex
+	buckets     map[Channel]*bucket
+	pausedUntil time.Time
+}
+
+// NewRateLimiter returns a RateLimiter using the default limit for keyType.
+func NewRateLimiter(keyType KeyType) *RateLimiter {
+	return &RateLimiter{Rate: DefaultRateLimits[keyType]}
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Key of the bucket shared by all requests
+const allChannels Channel = ""
+
+// Wait blocks until a request on ch is allowed, or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, ch Channel) error {
+	for {
+		wait := l.reserve(ch, time.Now())
+
+		if wait == 0 {
+			return nil
+		}
+
+		if l.FailFast {
+			return fmt.Errorf("%w: next request allowed in %s", ErrRateLimited, wait)
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return fmt.Errorf("%w: next request allowed in %s, after the context deadline", ErrRateLimited, wait)
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Throttle is called when Notify responds with a 429. It empties the buckets
+// so requests resume at the refill rate, and pauses all requests for d.
+func (l *RateLimiter) Throttle(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	for _, b := range l.buckets {
+		b.tokens = 0
+		b.last = now
+	}
+
+	if until := now.Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// reserve takes a token from every bucket that applies to ch, or returns how
+// long to wait until they all have one.
+func (l *RateLimiter) reserve(ch Channel, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	if l.buckets == nil {
+		l.buckets = map[Channel]*bucket{}
+	}
+
+	rates := map[Channel]Rate{allChannels: l.Rate}
+
+	if rate, ok := l.Channels[ch]; ok && ch != allChannels {
+		rates[ch] = rate
+	}
+
+	var wait time.Duration
+
+	for key, rate := range rates {
+		if rate.Requests <= 0 || rate.Per <= 0 {
+			continue
+		}
+
+		b, ok := l.buckets[key]
+
+		if !ok {
+			b = &bucket{tokens: float64(rate.Requests), last: now}
+			l.buckets[key] = b
+		}
+
+		perToken := rate.Per / time.Duration(rate.Requests)
+
+		b.tokens += float64(now.Sub(b.last)) / float64(perToken)
+		b.last = now
+
+		if b.tokens > float64(rate.Requests) {
+			b.tokens = float64(rate.Requests)
+		}
+
+		if b.tokens < 1 {
+			if d := time.Duration((1 - b.tokens) * float64(perToken)); d > wait {
+				wait = d
+			}
+		}
+	}
+
+	if wait > 0 {
+		return wait
+	}
+
+	for key := range rates {
+		if b, ok := l.buckets[key]; ok {
+			b.tokens--
+		}
+	}
+
+	return 0
+}
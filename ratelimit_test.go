@@ -0,0 +1,149 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestRateLimiterFailFast(t *testing.T) {
+	t.Parallel()
+
+	l := &RateLimiter{
+		Rate:     Rate{Requests: 2, Per: time.Hour},
+		FailFast: true,
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(context.Background(), ChannelEmail); err != nil {
+			t.Errorf("Expected request %d to be allowed, got %s", i+1, err)
+		}
+	}
+
+	if err := l.Wait(context.Background(), ChannelEmail); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestRateLimiterChannels(t *testing.T) {
+	t.Parallel()
+
+	l := &RateLimiter{
+		Rate: Rate{Requests: 100, Per: time.Hour},
+		Channels: map[Channel]Rate{
+			ChannelSms: {Requests: 1, Per: time.Hour},
+		},
+		FailFast: true,
+	}
+
+	if err := l.Wait(context.Background(), ChannelSms); err != nil {
+		t.Errorf("Expected first sms to be allowed, got %s", err)
+	}
+
+	if err := l.Wait(context.Background(), ChannelSms); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected second sms to be limited, got %v", err)
+	}
+
+	if err := l.Wait(context.Background(), ChannelEmail); err != nil {
+		t.Errorf("Expected email to be allowed, got %s", err)
+	}
+}
+
+func TestRateLimiterWaitsForNextToken(t *testing.T) {
+	t.Parallel()
+
+	l := &RateLimiter{Rate: Rate{Requests: 1, Per: 20 * time.Millisecond}}
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := l.Wait(context.Background(), ChannelEmail); err != nil {
+				t.Errorf("Expected no error, got %s", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected 3 requests to take at least 40ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterFailsFastBeforeDeadline(t *testing.T) {
+	t.Parallel()
+
+	l := NewRateLimiter(KeyTypeLive)
+	l.Rate = Rate{Requests: 1, Per: time.Hour}
+
+	l.Wait(context.Background(), ChannelEmail)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+
+	if err := l.Wait(ctx, ChannelEmail); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+
+	if time.Since(start) > 100*time.Millisecond {
+		t.Errorf("Expected Wait() to return without waiting for the deadline")
+	}
+}
+
+func TestRateLimiterThrottlesAfterTooManyRequests(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"status_code": 429, "errors": [{"error": "RateLimitError", "message": "Exceeded rate limit for key type LIVE of 1000 requests per 60 seconds"}]}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.RateLimiter = &RateLimiter{
+		Rate:     Rate{Requests: 1000, Per: time.Minute},
+		FailFast: true,
+	}
+
+	e := Email{
+		EmailAddress: "test@test.com",
+		TemplateId:   "00000000-0000-0000-0000-000000000000",
+	}
+
+	resp, err := c.SendEmail(e)
+
+	if err != nil || resp.StatusCode != 429 {
+		t.Fatalf("Expected a 429 response, got %d %v", resp.StatusCode, err)
+	}
+
+	_, err = c.SendEmail(e)
+
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d", calls.Load())
+	}
+}
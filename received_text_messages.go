@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/go-querystring/query"
+)
+
+type ReceivedTextMessage struct {
+	Id           string    `json:"id"`
+	UserNumber   string    `json:"user_number"`
+	NotifyNumber string    `json:"notify_number"`
+	CreatedAt    time.Time `json:"created_at"`
+	ServiceId    string    `json:"service_id"`
+	Content      string    `json:"content"`
+}
+
+type ReceivedTextMessages struct {
+	// Valid Response
+	ReceivedTextMessages []ReceivedTextMessage `json:"received_text_messages"`
+	Links                Link                  `json:"links"`
+
+	// Error Response
+	StatusCode int             `json:"status_code"`
+	Errors     []ResponseError `json:"errors"`
+}
+
+type ReceivedTextMessagesQueryOptions struct {
+	OlderThan string `url:"older_than,omitempty"`
+}
+
+func (c Client) GetReceivedTextMessages(options ReceivedTextMessagesQueryOptions) (ReceivedTextMessages, error) {
+	return c.GetReceivedTextMessagesContext(context.Background(), options)
+}
+
+func (c Client) GetReceivedTextMessagesContext(ctx context.Context, options ReceivedTextMessagesQueryOptions) (ReceivedTextMessages, error) {
+	v, _ := query.Values(options)
+
+	response, statusCode, err := doGet(ctx, c, "received text messages", "/v2/received-text-messages?"+v.Encode(), ReceivedTextMessages{})
+
+	if err != nil && statusCode == 0 {
+		return ReceivedTextMessages{}, err
+	}
+
+	response.StatusCode = statusCode
+
+	return response, err
+}
+
+func (s *ReceivedTextMessages) HasNext() bool {
+	return s.Links.Next != ""
+}
+
+func (c Client) NextReceivedTextMessagesPage(s ReceivedTextMessages) (ReceivedTextMessages, error) {
+	return c.NextReceivedTextMessagesPageContext(context.Background(), s)
+}
+
+func (c Client) NextReceivedTextMessagesPageContext(ctx context.Context, s ReceivedTextMessages) (ReceivedTextMessages, error) {
+	url, err := c.endpointFromLink(s.Links.Next)
+
+	if err != nil {
+		return ReceivedTextMessages{}, fmt.Errorf("error resolving next page: %s", err)
+	}
+
+	response, statusCode, err := doGet(ctx, c, "received text messages", url, ReceivedTextMessages{})
+
+	if err != nil && statusCode == 0 {
+		return ReceivedTextMessages{}, err
+	}
+
+	response.StatusCode = statusCode
+
+	return response, err
+}
+
+// InboundSms is the body of the callback sent by Notify when a text message
+// is received.
+type InboundSms struct {
+	Id                string    `json:"id"`
+	SourceNumber      string    `json:"source_number"`
+	DestinationNumber string    `json:"destination_number"`
+	Message           string    `json:"message"`
+	DateReceived      time.Time `json:"date_received"`
+}
+
+// InboundSmsHandler receives inbound text message callbacks from Notify,
+// with the same responses as DeliveryStatusHandler.
+type InboundSmsHandler struct {
+	// Accepted bearer tokens, set more than one while rotating them
+	Tokens []string
+
+	// Use a KeywordRouter's Route to dispatch on the message keyword
+	Handle func(ctx context.Context, m InboundSms) error
+
+	// Optional, messages seen before are acknowledged without calling Handle
+	Replays *ReplayCache
+}
+
+func (h InboundSmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveCallback(w, r, h.Tokens, h.Replays, func(m InboundSms) (string, error) {
+		if m.Id == "" {
+			return "", errors.New("missing id")
+		}
+
+		return "inbound-sms/" + m.Id, nil
+	}, func(ctx context.Context, m InboundSms) error {
+		if h.Handle == nil {
+			return nil
+		}
+
+		return h.Handle(ctx, m)
+	})
+}
+
+// KeywordRouter dispatches inbound text messages on their first word, such
+// as STOP or AIDE. Keywords are matched ignoring case, accents and trailing
+// punctuation, so "Arrêt." matches ARRET. Register keywords before routing
+// messages.
+type KeywordRouter struct {
+	// Optional, called for messages that don't match a keyword
+	Fallback func(ctx context.Context, m InboundSms) error
+
+	handlers map[string]func(ctx context.Context, m InboundSms) error
+}
+
+// Handle registers h for each keyword.
+func (r *KeywordRouter) Handle(h func(ctx context.Context, m InboundSms) error, keywords ...string) {
+	if r.handlers == nil {
+		r.handlers = map[string]func(ctx context.Context, m InboundSms) error{}
+	}
+
+	for _, k := range keywords {
+		r.handlers[normaliseKeyword(k)] = h
+	}
+}
+
+// Route calls the handler registered for the message keyword.
+func (r *KeywordRouter) Route(ctx context.Context, m InboundSms) error {
+	if h, ok := r.handlers[Keyword(m.Message)]; ok {
+		return h(ctx, m)
+	}
+
+	if r.Fallback != nil {
+		return r.Fallback(ctx, m)
+	}
+
+	return nil
+}
+
+// Keyword returns the normalised first word of a message.
+func Keyword(message string) string {
+	fields := strings.Fields(message)
+
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return normaliseKeyword(fields[0])
+}
+
+var accentReplacer = strings.NewReplacer(
+	"À", "A", "Â", "A", "Ä", "A",
+	"Ç", "C",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Î", "I", "Ï", "I",
+	"Ô", "O", "Ö", "O",
+	"Ù", "U", "Û", "U", "Ü", "U",
+	"Ÿ", "Y",
+)
+
+func normaliseKeyword(k string) string {
+	k = strings.TrimFunc(k, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+
+	return accentReplacer.Replace(strings.ToUpper(k))
+}
@@ -0,0 +1,144 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestGetReceivedTextMessages(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the request URL
+		if r.URL.Path != "/v2/received-text-messages" {
+			t.Errorf("Expected request to /v2/received-text-messages, got %s", r.URL.Path)
+		}
+
+		// Verify the request method
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+
+		response := ReceivedTextMessages{
+			ReceivedTextMessages: []ReceivedTextMessage{
+				{
+					Id:         "00000000-0000-0000-0000-000000000000",
+					UserNumber: "16135550123",
+					Content:    "STOP",
+				},
+			},
+		}
+
+		if r.URL.Query().Get("older_than") == "" {
+			response.Links.Next = "/v2/received-text-messages?older_than=00000000-0000-0000-0000-000000000000"
+		}
+
+		json.NewEncoder(w).Encode(response)
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	got, err := c.GetReceivedTextMessages(ReceivedTextMessagesQueryOptions{})
+
+	if err != nil {
+		t.Fatalf("Error calling GetReceivedTextMessages(): %s", err)
+	}
+
+	want := ReceivedTextMessages{
+		ReceivedTextMessages: []ReceivedTextMessage{
+			{
+				Id:         "00000000-0000-0000-0000-000000000000",
+				UserNumber: "16135550123",
+				Content:    "STOP",
+			},
+		},
+		Links: Link{
+			Next: "/v2/received-text-messages?older_than=00000000-0000-0000-0000-000000000000",
+		},
+		StatusCode: 200,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetReceivedTextMessages() = %v, want %v", got, want)
+	}
+
+	if !got.HasNext() {
+		t.Fatalf("Expected HasNext() to be true, got false")
+	}
+
+	got, err = c.NextReceivedTextMessagesPage(got)
+
+	if err != nil {
+		t.Fatalf("Error calling NextReceivedTextMessagesPage(): %s", err)
+	}
+
+	if got.HasNext() || len(got.ReceivedTextMessages) != 1 {
+		t.Errorf("Expected the last page, got %v", got)
+	}
+}
+
+func TestInboundSmsHandlerWithKeywordRouter(t *testing.T) {
+	t.Parallel()
+
+	var stopped, helped, other []string
+
+	router := &KeywordRouter{
+		Fallback: func(ctx context.Context, m InboundSms) error {
+			other = append(other, m.Message)
+			return nil
+		},
+	}
+
+	router.Handle(func(ctx context.Context, m InboundSms) error {
+		stopped = append(stopped, m.SourceNumber)
+		return nil
+	}, "STOP", "ARRET")
+
+	router.Handle(func(ctx context.Context, m InboundSms) error {
+		helped = append(helped, m.SourceNumber)
+		return nil
+	}, "help", "aide")
+
+	h := InboundSmsHandler{
+		Tokens: []string{"token"},
+		Handle: router.Route,
+	}
+
+	messages := []InboundSms{
+		{Id: "1", SourceNumber: "1", Message: "stop"},
+		{Id: "2", SourceNumber: "2", Message: " Arrêt. merci"},
+		{Id: "3", SourceNumber: "3", Message: "AIDE!"},
+		{Id: "4", SourceNumber: "4", Message: "Can I stop?"},
+	}
+
+	for _, m := range messages {
+		m.DateReceived = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		body, _ := json.Marshal(m)
+
+		if code := postCallback(h, "token", string(body)); code != http.StatusOK {
+			t.Errorf("Expected 200, got %d", code)
+		}
+	}
+
+	if !reflect.DeepEqual(stopped, []string{"1", "2"}) {
+		t.Errorf("Expected STOP from 1 and 2, got %v", stopped)
+	}
+
+	if !reflect.DeepEqual(helped, []string{"3"}) {
+		t.Errorf("Expected HELP from 3, got %v", helped)
+	}
+
+	if !reflect.DeepEqual(other, []string{"Can I stop?"}) {
+		t.Errorf("Expected other message to fall back, got %v", other)
+	}
+}
@@ -0,0 +1,325 @@
+package client
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// RenderedTemplate is a template with its personalisation applied. Html is
+// only set for email templates.
+type RenderedTemplate struct {
+	Subject string
+	Text    string
+	Html    string
+}
+
+// RenderTemplate renders t locally the way Notify formats it, without
+// calling the preview endpoint. Placeholders without a value are left as is.
+func RenderTemplate(t Template, personalisation map[string]interface{}) RenderedTemplate {
+	if t.Type == TemplateTypeSms {
+		return RenderedTemplate{Text: substitute(t.Body, personalisation, inlineList)}
+	}
+
+	return RenderedTemplate{
+		Subject: substitute(t.Subject, personalisation, inlineList),
+		Text:    RenderText(t.Body, personalisation),
+		Html:    RenderHTML(t.Body, personalisation),
+	}
+}
+
+// RenderText renders an email body as plain text.
+func RenderText(body string, personalisation map[string]interface{}) string {
+	var out []string
+
+	for _, b := range parseBlocks(substitute(body, personalisation, markdownList)) {
+		switch b.kind {
+		case blockHeading, blockSubHeading:
+			out = append(out, b.lines[0]+"\n"+strings.Repeat("-", len([]rune(b.lines[0]))))
+		case blockBullets:
+			items := make([]string, len(b.lines))
+
+			for i, line := range b.lines {
+				items[i] = "• " + textInline(line)
+			}
+
+			out = append(out, strings.Join(items, "\n"))
+		case blockNumbers:
+			items := make([]string, len(b.lines))
+
+			for i, line := range b.lines {
+				items[i] = fmt.Sprintf("%d. %s", i+1, textInline(line))
+			}
+
+			out = append(out, strings.Join(items, "\n"))
+		case blockRule:
+			out = append(out, strings.Repeat("=", 20))
+		default:
+			lines := make([]string, len(b.lines))
+
+			for i, line := range b.lines {
+				lines[i] = textInline(line)
+			}
+
+			out = append(out, strings.Join(lines, "\n"))
+		}
+	}
+
+	return strings.Join(out, "\n\n")
+}
+
+// RenderHTML renders an email body as HTML. The body and personalisation
+// are escaped, only Notify's formatting becomes markup.
+func RenderHTML(body string, personalisation map[string]interface{}) string {
+	var out []string
+
+	for _, b := range parseBlocks(substitute(body, personalisation, markdownList)) {
+		switch b.kind {
+		case blockHeading:
+			out = append(out, "<h2>"+htmlInline(b.lines[0])+"</h2>")
+		case blockSubHeading:
+			out = append(out, "<h3>"+htmlInline(b.lines[0])+"</h3>")
+		case blockBullets, blockNumbers:
+			tag := "ul"
+
+			if b.kind == blockNumbers {
+				tag = "ol"
+			}
+
+			items := make([]string, len(b.lines))
+
+			for i, line := range b.lines {
+				items[i] = "<li>" + htmlInline(line) + "</li>"
+			}
+
+			out = append(out, "<"+tag+">"+strings.Join(items, "")+"</"+tag+">")
+		case blockInset:
+			out = append(out, "<blockquote>"+htmlLines(b.lines)+"</blockquote>")
+		case blockRule:
+			out = append(out, "<hr>")
+		default:
+			out = append(out, "<p>"+htmlLines(b.lines)+"</p>")
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// substitute replaces placeholders with their value. Conditional
+// placeholders are replaced by their text when the value is true, and list
+// values are formatted with list.
+func substitute(s string, personalisation map[string]interface{}, list func([]string) string) string {
+	values := make(map[string]interface{}, len(personalisation))
+
+	for k, v := range personalisation {
+		values[normaliseKey(k)] = v
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		p := parsePlaceholder(match[2 : len(match)-2])
+
+		value, ok := values[normaliseKey(p.Name)]
+
+		if !ok {
+			return match
+		}
+
+		if p.Conditional {
+			if isTrue(value) {
+				return p.Text
+			}
+
+			return ""
+		}
+
+		if items, ok := listValue(value); ok {
+			return list(items)
+		}
+
+		if value == nil {
+			return ""
+		}
+
+		return fmt.Sprint(value)
+	})
+}
+
+// isTrue matches the values Notify accepts for showing conditional text.
+func isTrue(v interface{}) bool {
+	switch strings.ToLower(strings.TrimSpace(fmt.Sprint(v))) {
+	case "yes", "y", "true", "t", "1", "on":
+		return true
+	}
+
+	return false
+}
+
+func listValue(v interface{}) ([]string, bool) {
+	if v == nil {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	items := make([]string, rv.Len())
+
+	for i := range items {
+		items[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+
+	return items, true
+}
+
+// inlineList formats a list for subjects and text messages, as in "a, b and c".
+func inlineList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// markdownList puts a list on its own lines so it is formatted as bullets.
+func markdownList(items []string) string {
+	lines := make([]string, len(items))
+
+	for i, item := range items {
+		lines[i] = "* " + item
+	}
+
+	return "\n\n" + strings.Join(lines, "\n") + "\n\n"
+}
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockSubHeading
+	blockBullets
+	blockNumbers
+	blockInset
+	blockRule
+)
+
+type block struct {
+	kind  blockKind
+	lines []string
+}
+
+var (
+	headingPattern    = regexp.MustCompile(`^#\s+(.*)$`)
+	subHeadingPattern = regexp.MustCompile(`^##\s+(.*)$`)
+	rulePattern       = regexp.MustCompile(`^(-{3,}|\*{3,}|_{3,})\s*$`)
+	bulletPattern     = regexp.MustCompile(`^[*\-•]\s+(.*)$`)
+	numberPattern     = regexp.MustCompile(`^\d+\.\s+(.*)$`)
+	insetPattern      = regexp.MustCompile(`^\^\s*(.*)$`)
+)
+
+// parseBlocks splits an email body into headings, lists, inset text, rules
+// and paragraphs. Blocks end at a blank line or when a line starts a
+// different kind of block.
+func parseBlocks(s string) []block {
+	var blocks []block
+
+	// Index of the block lines are added to, -1 to start a new one
+	current := -1
+
+	add := func(kind blockKind, line string) {
+		if current < 0 || blocks[current].kind != kind {
+			blocks = append(blocks, block{kind: kind})
+			current = len(blocks) - 1
+		}
+
+		blocks[current].lines = append(blocks[current].lines, line)
+
+		// Headings and rules are a single line
+		if kind == blockHeading || kind == blockSubHeading || kind == blockRule {
+			current = -1
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			current = -1
+			continue
+		}
+
+		if m := subHeadingPattern.FindStringSubmatch(line); m != nil {
+			add(blockSubHeading, m[1])
+		} else if m := headingPattern.FindStringSubmatch(line); m != nil {
+			add(blockHeading, m[1])
+		} else if rulePattern.MatchString(line) {
+			add(blockRule, "")
+		} else if m := bulletPattern.FindStringSubmatch(line); m != nil {
+			add(blockBullets, m[1])
+		} else if m := numberPattern.FindStringSubmatch(line); m != nil {
+			add(blockNumbers, m[1])
+		} else if m := insetPattern.FindStringSubmatch(line); m != nil {
+			add(blockInset, m[1])
+		} else if current >= 0 && blocks[current].kind == blockInset {
+			// Inset text continues until a blank line
+			add(blockInset, line)
+		} else {
+			add(blockParagraph, line)
+		}
+	}
+
+	return blocks
+}
+
+var linkPattern = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)|https?://[^\s<>()]+`)
+
+// textInline writes markdown links as "text: url".
+func textInline(s string) string {
+	return linkPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if m := linkPattern.FindStringSubmatch(match); m[1] != "" {
+			return m[1] + ": " + m[2]
+		}
+
+		return match
+	})
+}
+
+// htmlInline escapes s and turns markdown links and bare URLs into anchors.
+func htmlInline(s string) string {
+	var b strings.Builder
+
+	last := 0
+
+	for _, m := range linkPattern.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(html.EscapeString(s[last:m[0]]))
+
+		text, href := s[m[0]:m[1]], s[m[0]:m[1]]
+
+		if m[2] >= 0 {
+			text, href = s[m[2]:m[3]], s[m[4]:m[5]]
+		}
+
+		fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(href), html.EscapeString(text))
+
+		last = m[1]
+	}
+
+	b.WriteString(html.EscapeString(s[last:]))
+
+	return b.String()
+}
+
+func htmlLines(lines []string) string {
+	escaped := make([]string, len(lines))
+
+	for i, line := range lines {
+		escaped[i] = htmlInline(line)
+	}
+
+	return strings.Join(escaped, "<br>")
+}
@@ -0,0 +1,118 @@
+package client_test
+
+import (
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+const renderBody = `# Your application
+
+Hello ((name)),
+thanks for applying.
+
+Bring:((documents))
+
+^ Your reference is ((reference)).
+
+((online??Apply online at https://example.com/apply))
+
+---
+
+1. Fill in the form
+2. Read the [guide](https://example.com/guide)`
+
+func TestRenderHTML(t *testing.T) {
+	t.Parallel()
+
+	got := RenderHTML(renderBody, map[string]interface{}{
+		"name":      "<Alex>",
+		"documents": []string{"Passport", "Photo"},
+		"reference": 1234,
+		"online":    "yes",
+	})
+
+	want := `<h2>Your application</h2>
+<p>Hello &lt;Alex&gt;,<br>thanks for applying.</p>
+<p>Bring:</p>
+<ul><li>Passport</li><li>Photo</li></ul>
+<blockquote>Your reference is 1234.</blockquote>
+<p>Apply online at <a href="https://example.com/apply">https://example.com/apply</a></p>
+<hr>
+<ol><li>Fill in the form</li><li>Read the <a href="https://example.com/guide">guide</a></li></ol>`
+
+	if got != want {
+		t.Errorf("RenderHTML() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	t.Parallel()
+
+	got := RenderText(renderBody, map[string]interface{}{
+		"name":      "Alex",
+		"documents": []string{"Passport", "Photo"},
+		"online":    false,
+	})
+
+	want := `Your application
+----------------
+
+Hello Alex,
+thanks for applying.
+
+Bring:
+
+• Passport
+• Photo
+
+Your reference is ((reference)).
+
+====================
+
+1. Fill in the form
+2. Read the guide: https://example.com/guide`
+
+	if got != want {
+		t.Errorf("RenderText() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	t.Parallel()
+
+	personalisation := map[string]interface{}{
+		"service":   "passport",
+		"documents": []string{"Passport", "Photo", "Form"},
+	}
+
+	got := RenderTemplate(Template{
+		Type:    TemplateTypeSms,
+		Body:    "# Bring ((documents)) for your ((service)) application",
+		Subject: "Ignored",
+	}, personalisation)
+
+	want := RenderedTemplate{
+		Text: "# Bring Passport, Photo and Form for your passport application",
+	}
+
+	if got != want {
+		t.Errorf("RenderTemplate() = %+v, want %+v", got, want)
+	}
+
+	got = RenderTemplate(Template{
+		Type:    TemplateTypeEmail,
+		Subject: "Your ((service)) application",
+		Body:    "Hello",
+	}, personalisation)
+
+	want = RenderedTemplate{
+		Subject: "Your passport application",
+		Text:    "Hello",
+		Html:    "<p>Hello</p>",
+	}
+
+	if got != want {
+		t.Errorf("RenderTemplate() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy controls how failed requests are retried. The zero value
+// retries up to 3 attempts on transport errors and 429, 502, 503 and 504
+// responses, backing off exponentially from 200ms to 10s.
+//
+// Retrying a POST after a transport error can send a notification twice if
+// Notify received the first request, set ShouldRetry if that is a concern.
+type RetryPolicy struct {
+	// Total number of attempts, including the first
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+
+	// Optional, defaults to 429, 502, 503 and 504
+	RetryableStatusCodes []int
+
+	// Optional, overrides RetryableStatusCodes and the transport error check
+	ShouldRetry func(resp *http.Response, err error) bool
+
+	// Optional, called after every attempt
+	OnAttempt func(a RetryAttempt)
+}
+
+type RetryAttempt struct {
+	Method   string
+	Endpoint string
+
+	// Starts at 1
+	Attempt    int
+	StatusCode int
+	Err        error
+
+	// How long until the next attempt, zero if there won't be one
+	Delay time.Duration
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 3
+	}
+
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(resp, err)
+	}
+
+	if err != nil {
+		// Waiting on the rate limiter or the caller won't help
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrRateLimited)
+	}
+
+	codes := p.RetryableStatusCodes
+
+	if codes == nil {
+		codes = defaultRetryableStatusCodes
+	}
+
+	return slices.Contains(codes, resp.StatusCode)
+}
+
+// backoff returns the delay before the given retry (starting at 1), using
+// exponential backoff with equal jitter.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	minBackoff, maxBackoff := p.MinBackoff, p.MaxBackoff
+
+	if minBackoff <= 0 {
+		minBackoff = 200 * time.Millisecond
+	}
+
+	if maxBackoff <= 0 {
+		maxBackoff = 10 * time.Second
+	}
+
+	d := minBackoff
+
+	for i := 1; i < retry && d < maxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+
+	half := d / 2
+
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func (p *RetryPolicy) do(ctx context.Context, method string, endpoint string, attempt func() (*http.Response, error)) (*http.Response, error) {
+	for i := 1; ; i++ {
+		resp, err := attempt()
+
+		a := RetryAttempt{
+			Method:   method,
+			Endpoint: endpoint,
+			Attempt:  i,
+			Err:      err,
+		}
+
+		if resp != nil {
+			a.StatusCode = resp.StatusCode
+		}
+
+		retry := i < p.maxAttempts() && ctx.Err() == nil && p.shouldRetry(resp, err)
+
+		if retry {
+			a.Delay = p.backoff(i)
+
+			if d, ok := retryAfter(resp); ok {
+				a.Delay = d
+			}
+
+			// Hand back what we have rather than wait past the deadline
+			if deadline, ok := ctx.Deadline(); ok && time.Now().Add(a.Delay).After(deadline) {
+				retry = false
+				a.Delay = 0
+			}
+		}
+
+		if p.OnAttempt != nil {
+			p.OnAttempt(a)
+		}
+
+		if !retry {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(a.Delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryAfter parses the Retry-After header of a 429 or 503 response, which
+// can either be a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		d := time.Until(date)
+
+		if d < 0 {
+			d = 0
+		}
+
+		return d, true
+	}
+
+	return 0, false
+}
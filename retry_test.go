@@ -0,0 +1,187 @@
+package client_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestRetryReplaysBodyAfterServerError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the body is sent on every attempt
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Error reading request body: %s", err)
+		}
+
+		if string(body) != "test" {
+			t.Errorf("Expected request body to be test, got %s", body)
+		}
+
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var attempts []RetryAttempt
+
+	client := &Client{
+		ApiKey:     "test",
+		HttpClient: http.Client{},
+		Hostname:   server.URL,
+		Retry: &RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  5 * time.Millisecond,
+			OnAttempt: func(a RetryAttempt) {
+				attempts = append(attempts, a)
+			},
+		},
+	}
+
+	resp, err := client.DoPostRequest("/v2/notifications/email", []byte("test"))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if resp.StatusCode != 201 {
+		t.Errorf("Expected status code to be 201, got %d", resp.StatusCode)
+	}
+
+	if len(attempts) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", len(attempts))
+	}
+
+	if attempts[0].StatusCode != 503 || attempts[0].Delay == 0 {
+		t.Errorf("Expected first attempt to be retried after a 503, got %+v", attempts[0])
+	}
+
+	if attempts[2].Attempt != 3 || attempts[2].Delay != 0 {
+		t.Errorf("Expected last attempt not to be retried, got %+v", attempts[2])
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var delays []time.Duration
+
+	client := &Client{
+		ApiKey:     "test",
+		HttpClient: http.Client{},
+		Hostname:   server.URL,
+		Retry: &RetryPolicy{
+			MinBackoff: time.Hour,
+			OnAttempt: func(a RetryAttempt) {
+				delays = append(delays, a.Delay)
+			},
+		},
+	}
+
+	resp, err := client.DoGetRequest("/v2/notifications")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if resp.StatusCode != 200 {
+		t.Errorf("Expected status code to be 200, got %d", resp.StatusCode)
+	}
+
+	if len(delays) != 2 || delays[0] != 0 {
+		t.Errorf("Expected a single retry with no delay, got %v", delays)
+	}
+}
+
+func TestRetryDoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		ApiKey:     "test",
+		HttpClient: http.Client{},
+		Hostname:   server.URL,
+		Retry:      &RetryPolicy{MinBackoff: time.Millisecond},
+	}
+
+	resp, err := client.DoPostRequest("/v2/notifications/email", []byte("test"))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if resp.StatusCode != 400 {
+		t.Errorf("Expected status code to be 400, got %d", resp.StatusCode)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 request, got %d", calls.Load())
+	}
+}
+
+func TestRetryReturnsLastResponseWhenAttemptsExhausted(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		ApiKey:     "test",
+		HttpClient: http.Client{},
+		Hostname:   server.URL,
+		Retry:      &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond},
+	}
+
+	resp, err := client.DoGetRequest("/v2/notifications")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if resp.StatusCode != 502 {
+		t.Errorf("Expected status code to be 502, got %d", resp.StatusCode)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 requests, got %d", calls.Load())
+	}
+}
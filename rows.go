@@ -0,0 +1,257 @@
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// RowMapper builds the rows of a bulk send from structs. Fields are mapped to
+// columns with a notify tag naming the column, as in
+//
+//	type Recipient struct {
+//		Email string    `notify:"email address"`
+//		Name  string    `notify:"name"`
+//		Due   time.Time `notify:"due date"`
+//	}
+//
+// Fields without a tag, or tagged "-", are skipped. Fields of exported
+// embedded structs are mapped as if they were fields of the outer struct.
+type RowMapper struct {
+	// Optional, defaults to the date as 2006-01-02
+	FormatTime func(time.Time) string
+
+	// Optional, default to the shortest decimal representation
+	FormatInt   func(int64) string
+	FormatFloat func(float64) string
+
+	// Optional, defaults to yes or no
+	FormatBool func(bool) string
+
+	// Optional, detected from the recipient column when empty
+	Type TemplateType
+}
+
+type rowColumn struct {
+	name  string
+	index []int
+}
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	stringerType = reflect.TypeFor[fmt.Stringer]()
+)
+
+// RowsFrom returns a header built from the notify tags of T followed by a row
+// per item, formatted with the defaults of RowMapper.
+func RowsFrom[T any](items []T) ([][]string, error) {
+	return MapRows(RowMapper{}, items)
+}
+
+// MapRows returns a header built from the notify tags of T followed by a row
+// per item. It returns an error matching ErrInvalidBulk when the header is
+// missing the recipient columns of m.Type, or an item has no recipient.
+func MapRows[T any](m RowMapper, items []T) ([][]string, error) {
+	typ := reflect.TypeFor[T]()
+
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't map rows from %s, expected a struct", typ)
+	}
+
+	columns, err := structColumns(typ, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]string, len(columns))
+	seen := map[string]string{}
+
+	for i, col := range columns {
+		if other, ok := seen[normaliseKey(col.name)]; ok {
+			return nil, fmt.Errorf("columns %q and %q of %s are the same to Notify", other, col.name, typ)
+		}
+
+		seen[normaliseKey(col.name)] = col.name
+		header[i] = col.name
+	}
+
+	rows := make([][]string, 0, len(items)+1)
+	rows = append(rows, header)
+
+	for n, item := range items {
+		v := reflect.ValueOf(item)
+
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, fmt.Errorf("item %d is nil", n)
+			}
+
+			v = v.Elem()
+		} else {
+			// Copied so fields are addressable for Stringers with a pointer
+			// receiver
+			copied := reflect.New(typ).Elem()
+			copied.Set(v)
+			v = copied
+		}
+
+		row := make([]string, len(columns))
+
+		for i, col := range columns {
+			field, err := v.FieldByIndexErr(col.index)
+
+			// A nil embedded pointer leaves its fields empty
+			if err != nil {
+				continue
+			}
+
+			row[i] = m.format(field)
+		}
+
+		rows = append(rows, row)
+	}
+
+	if _, _, err := (Bulk{Rows: rows, Type: m.Type}).check(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// structColumns returns the tagged fields of typ, checking each can be
+// formatted.
+func structColumns(typ reflect.Type, index []int) ([]rowColumn, error) {
+	var columns []rowColumn
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		tag, tagged := f.Tag.Lookup("notify")
+
+		if f.Anonymous && !tagged && f.IsExported() {
+			embedded := f.Type
+
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				nested, err := structColumns(embedded, fieldIndex)
+
+				if err != nil {
+					return nil, err
+				}
+
+				columns = append(columns, nested...)
+			}
+
+			continue
+		}
+
+		if !tagged || tag == "-" || !f.IsExported() {
+			continue
+		}
+
+		if !isFormattable(f.Type) {
+			return nil, fmt.Errorf("can't map field %s of type %s to a column", f.Name, f.Type)
+		}
+
+		columns = append(columns, rowColumn{name: tag, index: fieldIndex})
+	}
+
+	return columns, nil
+}
+
+func isFormattable(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType || t.Implements(stringerType) || reflect.PointerTo(t).Implements(stringerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// format returns the cell for v, which is empty for a nil pointer or a zero
+// time.
+func (m RowMapper) format(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+
+		switch {
+		case t.IsZero():
+			return ""
+		case m.FormatTime != nil:
+			return m.FormatTime(t)
+		default:
+			return t.Format(time.DateOnly)
+		}
+	}
+
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	if v.CanAddr() {
+		if s, ok := v.Addr().Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if m.FormatBool != nil {
+			return m.FormatBool(v.Bool())
+		}
+
+		if v.Bool() {
+			return "yes"
+		}
+
+		return "no"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if m.FormatInt != nil {
+			return m.FormatInt(v.Int())
+		}
+
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if m.FormatInt != nil && v.Uint() <= 1<<63-1 {
+			return m.FormatInt(int64(v.Uint()))
+		}
+
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		if m.FormatFloat != nil {
+			return m.FormatFloat(v.Float())
+		}
+
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	}
+
+	return v.String()
+}
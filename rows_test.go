@@ -0,0 +1,173 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+type Contact struct {
+	Name string `notify:"name"`
+}
+
+type reminder struct {
+	Contact
+	Email    string    `notify:"email address"`
+	Due      time.Time `notify:"due date"`
+	Amount   float64   `notify:"amount"`
+	Visits   int       `notify:"visits"`
+	Urgent   bool      `notify:"urgent"`
+	Status   KeyType   `notify:"status"`
+	Note     *string   `notify:"note"`
+	Internal string    `notify:"-"`
+	Untagged string
+	Renewed  *time.Time `notify:"renewed"`
+}
+
+func TestRowsFrom(t *testing.T) {
+	t.Parallel()
+
+	note := "Bring ID"
+
+	got, err := RowsFrom([]reminder{
+		{Contact: Contact{Name: "Alex"}, Email: "alex@example.com", Due: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), Amount: 12.5, Visits: 3, Urgent: true, Status: KeyTypeLive, Note: &note},
+		{Contact: Contact{Name: "Sam"}, Email: "sam@example.com"},
+	})
+
+	if err != nil {
+		t.Fatalf("RowsFrom returned an error: %s", err)
+	}
+
+	want := [][]string{
+		{"name", "email address", "due date", "amount", "visits", "urgent", "status", "note", "renewed"},
+		{"Alex", "alex@example.com", "2024-03-01", "12.5", "3", "yes", "normal", "Bring ID", ""},
+		{"Sam", "sam@example.com", "", "0", "0", "no", "", "", ""},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RowsFrom() = %v, want %v", got, want)
+	}
+}
+
+// code has a String method with a pointer receiver
+type code struct {
+	value int
+}
+
+func (c *code) String() string {
+	return fmt.Sprintf("C-%03d", c.value)
+}
+
+func TestRowsFromPointerStringer(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		Email string `notify:"email address"`
+		Code  code   `notify:"code"`
+	}
+
+	for name, items := range map[string]any{
+		"values":   []row{{Email: "alex@example.com", Code: code{7}}},
+		"pointers": []*row{{Email: "alex@example.com", Code: code{7}}},
+	} {
+		var (
+			got [][]string
+			err error
+		)
+
+		switch items := items.(type) {
+		case []row:
+			got, err = RowsFrom(items)
+		case []*row:
+			got, err = RowsFrom(items)
+		}
+
+		if err != nil {
+			t.Fatalf("%s: RowsFrom returned an error: %s", name, err)
+		}
+
+		if got[1][1] != "C-007" {
+			t.Errorf("%s: Expected the code to be formatted by String, got %q", name, got[1][1])
+		}
+	}
+}
+
+func TestMapRowsFormatters(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		Phone  string    `notify:"phone number"`
+		Due    time.Time `notify:"due"`
+		Amount float64   `notify:"amount"`
+		Visits uint      `notify:"visits"`
+		Urgent bool      `notify:"urgent"`
+	}
+
+	m := RowMapper{
+		FormatTime:  func(t time.Time) string { return t.Format("2 January 2006") },
+		FormatFloat: func(f float64) string { return strings.Replace(fmt.Sprint(f), ".", ",", 1) + " $" },
+		FormatInt:   func(i int64) string { return "#" + fmt.Sprint(i) },
+		FormatBool:  func(b bool) string { return map[bool]string{true: "oui", false: "non"}[b] },
+	}
+
+	got, err := MapRows(m, []*row{{Phone: "+16135550123", Due: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Amount: 12.5, Visits: 2, Urgent: true}})
+
+	if err != nil {
+		t.Fatalf("MapRows returned an error: %s", err)
+	}
+
+	want := []string{"+16135550123", "1 March 2024", "12,5 $", "#2", "oui"}
+
+	if !reflect.DeepEqual(got[1], want) {
+		t.Errorf("MapRows() row = %v, want %v", got[1], want)
+	}
+}
+
+func TestRowsFromErrors(t *testing.T) {
+	t.Parallel()
+
+	type noRecipient struct {
+		Name string `notify:"name"`
+	}
+
+	if _, err := RowsFrom([]noRecipient{{Name: "Alex"}}); !errors.Is(err, ErrInvalidBulk) {
+		t.Errorf("Expected ErrInvalidBulk for a missing recipient column, got %v", err)
+	}
+
+	// A row with only empty cells would be skipped as blank
+	type emptyRecipient struct {
+		Email string `notify:"email address"`
+		Name  string `notify:"name"`
+	}
+
+	if _, err := RowsFrom([]emptyRecipient{{Name: "Alex"}}); !errors.Is(err, ErrInvalidBulk) {
+		t.Errorf("Expected ErrInvalidBulk for an empty recipient, got %v", err)
+	}
+
+	type duplicate struct {
+		Email string `notify:"email address"`
+		Other string `notify:"Email_Address"`
+	}
+
+	if _, err := RowsFrom([]duplicate{{}}); err == nil || !strings.Contains(err.Error(), "are the same to Notify") {
+		t.Errorf("Expected an error for duplicate columns, got %v", err)
+	}
+
+	type unsupported struct {
+		Email string   `notify:"email address"`
+		Tags  []string `notify:"tags"`
+	}
+
+	if _, err := RowsFrom([]unsupported{{}}); err == nil || !strings.Contains(err.Error(), "can't map field Tags") {
+		t.Errorf("Expected an error for an unsupported field, got %v", err)
+	}
+
+	if _, err := RowsFrom([]string{"alex@example.com"}); err == nil {
+		t.Errorf("Expected an error for items that aren't structs")
+	}
+}
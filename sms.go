@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -16,6 +17,10 @@ type Sms struct {
 }
 
 func (c Client) SendSms(s Sms) (Response, error) {
+	return c.SendSmsContext(context.Background(), s)
+}
+
+func (c Client) SendSmsContext(ctx context.Context, s Sms) (Response, error) {
 	body, err := json.Marshal(s)
 
 	var response Response
@@ -24,21 +29,35 @@ func (c Client) SendSms(s Sms) (Response, error) {
 		return response, fmt.Errorf("error marshalling body: %s", err)
 	}
 
-	resp, err := c.DoPostRequest("/v2/notifications/sms", body)
+	if err := c.checkPinnedVersion(ctx, s.TemplateId); err != nil {
+		return response, err
+	}
+
+	release, err := c.reserveDailyLimit(ctx, ChannelSms, 1)
 
 	if err != nil {
-		return response, fmt.Errorf("error calling sms endpoint: %s", err)
+		return response, err
 	}
 
-	defer resp.Body.Close()
+	defer func() { release(response.StatusCode) }()
 
-	err = json.NewDecoder(resp.Body).Decode(&response)
+	resp, err := c.doRequest(ctx, ChannelSms, "POST", "/v2/notifications/sms", body)
 
 	if err != nil {
-		return response, fmt.Errorf("error decoding sms response: %s", err)
+		return response, fmt.Errorf("error calling sms endpoint: %w", err)
 	}
 
+	raw, err := readResponse(resp, &response)
+
 	response.StatusCode = resp.StatusCode
 
-	return response, nil
+	if err != nil {
+		return response, fmt.Errorf("error decoding sms response: %w", err)
+	}
+
+	if err := c.checkResponse(resp.StatusCode, raw); err != nil {
+		return response, err
+	}
+
+	return response, c.checkSentVersion(s.TemplateId, response.Template.Version)
 }
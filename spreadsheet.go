@@ -0,0 +1,579 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxRepeat bounds the rows and cells a spreadsheet can repeat, as formats
+// pad sheets with repeated empty ones up to their largest size.
+const maxRepeat = MaxBulkRows + 1
+
+// NewBulkFromXLSX returns a bulk send streaming its rows from the first sheet
+// of an Excel workbook. Cells are read as stored, so dates are their serial
+// number and should be formatted as text in the sheet.
+//
+// A workbook is a zip archive, which can't be read as a stream. r is read into
+// memory unless it is an *os.File or another io.ReaderAt with a Stat method.
+func NewBulkFromXLSX(name string, templateId string, r io.Reader) (Bulk, error) {
+	archive, err := openZip(r)
+
+	if err != nil {
+		return Bulk{}, err
+	}
+
+	sheet, err := xlsxFirstSheet(archive)
+
+	if err != nil {
+		return Bulk{}, err
+	}
+
+	var shared []string
+
+	if f, err := archive.Open("xl/sharedStrings.xml"); err == nil {
+		shared, err = xlsxSharedStrings(f)
+		f.Close()
+
+		if err != nil {
+			return Bulk{}, fmt.Errorf("error reading shared strings: %w", err)
+		}
+	}
+
+	f, err := archive.Open(sheet)
+
+	if err != nil {
+		return Bulk{}, fmt.Errorf("error opening %s: %w", sheet, err)
+	}
+
+	return Bulk{Name: name, TemplateId: templateId, Source: &xlsxReader{shared: shared, f: f, dec: xml.NewDecoder(f)}}, nil
+}
+
+// NewBulkFromODS returns a bulk send streaming its rows from the first table
+// of an OpenDocument spreadsheet. It reads r the way NewBulkFromXLSX does.
+func NewBulkFromODS(name string, templateId string, r io.Reader) (Bulk, error) {
+	archive, err := openZip(r)
+
+	if err != nil {
+		return Bulk{}, err
+	}
+
+	f, err := archive.Open("content.xml")
+
+	if err != nil {
+		return Bulk{}, fmt.Errorf("error opening content.xml: %w", err)
+	}
+
+	return Bulk{Name: name, TemplateId: templateId, Source: &odsReader{f: f, dec: xml.NewDecoder(f)}}, nil
+}
+
+func openZip(r io.Reader) (*zip.Reader, error) {
+	if f, ok := r.(interface {
+		io.ReaderAt
+		Stat() (fs.FileInfo, error)
+	}); ok {
+		if info, err := f.Stat(); err == nil {
+			return zip.NewReader(f, info.Size())
+		}
+	}
+
+	data, err := io.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return zip.NewReader(bytes.NewReader(data), int64(len(data)))
+}
+
+// xlsxFirstSheet returns the path in archive of the first sheet of the
+// workbook.
+func xlsxFirstSheet(archive *zip.Reader) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			Id string `xml:"id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+
+	var rels struct {
+		Relationships []struct {
+			Id     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	if err := unmarshalZipFile(archive, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("workbook has no sheets")
+	}
+
+	if err := unmarshalZipFile(archive, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.Id != workbook.Sheets[0].Id {
+			continue
+		}
+
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "", fmt.Errorf("sheet %s not found in workbook", workbook.Sheets[0].Id)
+}
+
+func unmarshalZipFile(archive *zip.Reader, name string, v any) error {
+	f, err := archive.Open(name)
+
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", name, err)
+	}
+
+	defer f.Close()
+
+	if err := xml.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("error decoding %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// xlsxSharedStrings returns the strings cells refer to by index. Phonetic
+// runs are left out.
+func xlsxSharedStrings(r io.Reader) ([]string, error) {
+	var (
+		shared []string
+		text   strings.Builder
+		inText bool
+		skip   int
+	)
+
+	dec := xml.NewDecoder(r)
+
+	for {
+		tok, err := dec.Token()
+
+		if err == io.EOF {
+			return shared, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case tok.Name.Local == "rPh" || skip > 0:
+				skip++
+			case tok.Name.Local == "si":
+				text.Reset()
+			case tok.Name.Local == "t":
+				inText = true
+			}
+		case xml.EndElement:
+			switch {
+			case skip > 0:
+				skip--
+			case tok.Name.Local == "si":
+				shared = append(shared, text.String())
+			case tok.Name.Local == "t":
+				inText = false
+			}
+		case xml.CharData:
+			if inText && skip == 0 {
+				text.Write(tok)
+			}
+		}
+	}
+}
+
+type xlsxReader struct {
+	shared []string
+	f      io.Closer
+	dec    *xml.Decoder
+
+	// Number of the last row read
+	number int
+}
+
+// FieldPos returns the row and column numbers of a cell of the last row
+// read, as shown by a spreadsheet.
+func (x *xlsxReader) FieldPos(field int) (int, int) {
+	return x.number, field + 1
+}
+
+func (x *xlsxReader) Read() ([]string, error) {
+	for {
+		tok, err := x.dec.Token()
+
+		if err == io.EOF {
+			x.f.Close()
+			return nil, io.EOF
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "row" {
+			// Rows without cells may be left out, so the reference is used
+			// when there is one
+			x.number++
+
+			for _, attr := range start.Attr {
+				if n, err := strconv.Atoi(attr.Value); attr.Name.Local == "r" && err == nil && n > 0 {
+					x.number = n
+				}
+			}
+
+			row, err := x.readRow()
+
+			if err != nil {
+				return nil, err
+			}
+
+			// Notify skips blank rows, and sheets often have formatted ones
+			if !isBlankRow(row) {
+				return row, nil
+			}
+		}
+	}
+}
+
+// readRow reads the cells of a row, placing each in the column of its
+// reference as empty cells may be left out.
+func (x *xlsxReader) readRow() ([]string, error) {
+	var row []string
+
+	for {
+		tok, err := x.dec.Token()
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if tok.Name.Local != "c" {
+				continue
+			}
+
+			column := len(row)
+			cellType := ""
+
+			for _, attr := range tok.Attr {
+				switch attr.Name.Local {
+				case "r":
+					if c, ok := xlsxColumn(attr.Value); ok {
+						column = c
+					}
+				case "t":
+					cellType = attr.Value
+				}
+			}
+
+			value, err := x.readCell(cellType)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if column >= maxRepeat {
+				return nil, fmt.Errorf("cell %d is past the last column", column+1)
+			}
+
+			for len(row) <= column {
+				row = append(row, "")
+			}
+
+			row[column] = value
+		case xml.EndElement:
+			if tok.Name.Local == "row" {
+				return row, nil
+			}
+		}
+	}
+}
+
+func (x *xlsxReader) readCell(cellType string) (string, error) {
+	var value strings.Builder
+
+	inValue := false
+
+	for {
+		tok, err := x.dec.Token()
+
+		if err != nil {
+			return "", err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			inValue = tok.Name.Local == "v" || tok.Name.Local == "t"
+		case xml.EndElement:
+			inValue = false
+
+			if tok.Name.Local != "c" {
+				continue
+			}
+
+			s := value.String()
+
+			switch cellType {
+			case "s":
+				i, err := strconv.Atoi(s)
+
+				if err != nil || i < 0 || i >= len(x.shared) {
+					return "", fmt.Errorf("invalid shared string %q", s)
+				}
+
+				return x.shared[i], nil
+			case "b":
+				return strings.ToUpper(strconv.FormatBool(s == "1")), nil
+			default:
+				return s, nil
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(tok)
+			}
+		}
+	}
+}
+
+// xlsxColumn returns the column index of a cell reference such as "C12".
+func xlsxColumn(ref string) (int, bool) {
+	column := 0
+
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+
+		column = column*26 + int(r-'A'+1)
+	}
+
+	return column - 1, column > 0
+}
+
+type odsReader struct {
+	f       io.Closer
+	dec     *xml.Decoder
+	inTable bool
+	done    bool
+
+	// A row to return again, and how many more times
+	repeat      []string
+	repeatCount int
+
+	// Number of the last row read, counting repeated and blank rows
+	number int
+}
+
+// FieldPos returns the row and column numbers of a cell of the last row
+// read, as shown by a spreadsheet.
+func (o *odsReader) FieldPos(field int) (int, int) {
+	return o.number, field + 1
+}
+
+func (o *odsReader) Read() ([]string, error) {
+	if o.repeatCount > 0 {
+		o.repeatCount--
+		o.number++
+		return slices.Clone(o.repeat), nil
+	}
+
+	for !o.done {
+		tok, err := o.dec.Token()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case tok.Name.Local == "table" && !o.inTable:
+				o.inTable = true
+			case tok.Name.Local == "table-row" && o.inTable:
+				row, err := o.readRow()
+
+				if err != nil {
+					return nil, err
+				}
+
+				repeat := odsRepeat(tok, "number-rows-repeated")
+
+				if isBlankRow(row) {
+					o.number += repeat
+					continue
+				}
+
+				o.number++
+				o.repeat = row
+				o.repeatCount = min(repeat, maxRepeat) - 1
+
+				return row, nil
+			}
+		case xml.EndElement:
+			// Only the first table is read
+			if tok.Name.Local == "table" && o.inTable {
+				o.done = true
+			}
+		}
+	}
+
+	o.done = true
+	o.f.Close()
+
+	return nil, io.EOF
+}
+
+// readRow reads the cells of a row, leaving out empty cells at its end.
+func (o *odsReader) readRow() ([]string, error) {
+	var row []string
+
+	// Empty cells only added once a cell with a value follows
+	empty := 0
+
+	for {
+		tok, err := o.dec.Token()
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if tok.Name.Local != "table-cell" && tok.Name.Local != "covered-table-cell" {
+				continue
+			}
+
+			value, err := o.readCell(tok)
+
+			if err != nil {
+				return nil, err
+			}
+
+			repeat := min(odsRepeat(tok, "number-columns-repeated"), maxRepeat)
+
+			if value == "" {
+				empty += repeat
+				continue
+			}
+
+			if len(row)+empty+repeat > maxRepeat {
+				return nil, fmt.Errorf("row has more than %d cells", maxRepeat)
+			}
+
+			for ; empty > 0; empty-- {
+				row = append(row, "")
+			}
+
+			for range repeat {
+				row = append(row, value)
+			}
+		case xml.EndElement:
+			if tok.Name.Local == "table-row" {
+				return row, nil
+			}
+		}
+	}
+}
+
+// readCell returns the value of a cell, using the value attributes of
+// numbers, dates and booleans and the text of its paragraphs otherwise.
+func (o *odsReader) readCell(start xml.StartElement) (string, error) {
+	value, typed := "", false
+
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "value", "date-value", "time-value", "boolean-value":
+			value, typed = attr.Value, true
+		}
+	}
+
+	var (
+		text       strings.Builder
+		paragraphs int
+		skip       int
+	)
+
+	for {
+		tok, err := o.dec.Token()
+
+		if err != nil {
+			return "", err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case tok.Name.Local == "annotation" || skip > 0:
+				skip++
+			case tok.Name.Local == "p":
+				if paragraphs > 0 {
+					text.WriteByte('\n')
+				}
+
+				paragraphs++
+			case tok.Name.Local == "s":
+				text.WriteString(strings.Repeat(" ", min(odsRepeat(tok, "c"), 1024)))
+			case tok.Name.Local == "tab":
+				text.WriteByte('\t')
+			case tok.Name.Local == "line-break":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+
+			if tok.Name == start.Name {
+				if typed {
+					return value, nil
+				}
+
+				return text.String(), nil
+			}
+		case xml.CharData:
+			if skip == 0 {
+				text.Write(tok)
+			}
+		}
+	}
+}
+
+// odsRepeat returns the repeat count in the attribute name of an element,
+// which is 1 when it isn't set.
+func odsRepeat(start xml.StartElement, name string) int {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			if n, err := strconv.Atoi(attr.Value); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+
+	return 1
+}
@@ -0,0 +1,190 @@
+package client_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func zipFiles(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		f, err := w.Create(name)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f.Write([]byte(content))
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestNewBulkFromXLSX(t *testing.T) {
+	t.Parallel()
+
+	archive := zipFiles(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Contacts" sheetId="2" r:id="rId3"/><sheet name="Other" sheetId="1" r:id="rId1"/></sheets>
+		</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId1" Target="worksheets/sheet1.xml"/>
+			<Relationship Id="rId3" Target="/xl/worksheets/sheet2.xml"/>
+		</Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+			<si><t>Email Address</t></si>
+			<si><t>name</t></si>
+			<si><r><t>Élise </t></r><r><t>Côté</t></r><rPh><t>ignored</t></rPh></si>
+		</sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>wrong sheet</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="inlineStr"><is><t>visits</t></is></c></row>
+			<row r="2"><c r="A2" t="inlineStr"><is><t>elise@example.com</t></is></c><c r="B2" t="s"><v>2</v></c><c r="C2" t="b"><v>1</v></c><c r="D2"><f>1+2</f><v>3</v></c></row>
+			<row r="3"><c r="B3" s="1"/></row>
+			<row r="5"><c r="A5" t="str"><v>sam@example.com</v></c></row>
+		</sheetData></worksheet>`,
+	})
+
+	b, err := NewBulkFromXLSX("Test", "00000000-0000-0000-0000-000000000000", archive)
+
+	if err != nil {
+		t.Fatalf("NewBulkFromXLSX returned an error: %s", err)
+	}
+
+	want := [][]string{
+		{"Email Address", "name", "", "visits"},
+		{"elise@example.com", "Élise Côté", "TRUE", "3"},
+		{"sam@example.com"},
+	}
+
+	if got := readRows(t, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
+
+func TestNewBulkFromODS(t *testing.T) {
+	t.Parallel()
+
+	archive := zipFiles(t, map[string]string{
+		"content.xml": `<office:document-content
+			xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+			xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+			xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+			<office:body><office:spreadsheet>
+				<table:table table:name="Contacts">
+					<table:table-row>
+						<table:table-cell office:value-type="string"><text:p>phone number</text:p></table:table-cell>
+						<table:table-cell table:number-columns-repeated="2"/>
+						<table:table-cell office:value-type="string"><text:p>note</text:p></table:table-cell>
+						<table:table-cell table:number-columns-repeated="1020"/>
+					</table:table-row>
+					<table:table-row table:number-rows-repeated="2">
+						<table:table-cell office:value-type="float" office:value="6135550123"><text:p>6 135 550 123</text:p></table:table-cell>
+						<table:table-cell office:value-type="boolean" office:boolean-value="true"><text:p>VRAI</text:p></table:table-cell>
+						<table:table-cell table:number-columns-repeated="2" office:value-type="string"><text:p>a<text:s text:c="2"/>b</text:p><text:p>c</text:p><office:annotation><text:p>comment</text:p></office:annotation></table:table-cell>
+					</table:table-row>
+					<table:table-row table:number-rows-repeated="1048570"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+				</table:table>
+				<table:table table:name="Other">
+					<table:table-row><table:table-cell office:value-type="string"><text:p>ignored</text:p></table:table-cell></table:table-row>
+				</table:table>
+			</office:spreadsheet></office:body>
+		</office:document-content>`,
+	})
+
+	b, err := NewBulkFromODS("Test", "00000000-0000-0000-0000-000000000000", archive)
+
+	if err != nil {
+		t.Fatalf("NewBulkFromODS returned an error: %s", err)
+	}
+
+	want := [][]string{
+		{"phone number", "", "", "note"},
+		{"6135550123", "true", "a  b\nc", "a  b\nc"},
+		{"6135550123", "true", "a  b\nc", "a  b\nc"},
+	}
+
+	if got := readRows(t, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
+
+func TestNewBulkFromXLSXInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewBulkFromXLSX("Test", "00000000-0000-0000-0000-000000000000", bytes.NewReader([]byte("email address\n"))); err == nil {
+		t.Errorf("Expected an error for a file that isn't a workbook")
+	}
+
+	if _, err := NewBulkFromODS("Test", "00000000-0000-0000-0000-000000000000", zipFiles(t, map[string]string{"mimetype": "x"})); err == nil {
+		t.Errorf("Expected an error for a spreadsheet without content")
+	}
+}
+
+func TestSpreadsheetRowNumbers(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	xlsx, _ := NewBulkFromXLSX("Test", "00000000-0000-0000-0000-000000000000", zipFiles(t, map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns:r="r"><sheets><sheet r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="inlineStr"><is><t>email address</t></is></c><c r="B1" t="inlineStr"><is><t>name</t></is></c></row>
+			<row r="2"><c r="A2" t="inlineStr"><is><t>alex@example.com</t></is></c></row>
+			<row r="5"><c r="B5" t="inlineStr"><is><t>Sam</t></is></c></row>
+		</sheetData></worksheet>`,
+	}))
+
+	ods, _ := NewBulkFromODS("Test", "00000000-0000-0000-0000-000000000000", zipFiles(t, map[string]string{
+		"content.xml": `<document-content><body><spreadsheet><table>
+			<table-row><table-cell><p>phone number</p></table-cell><table-cell><p>name</p></table-cell></table-row>
+			<table-row number-rows-repeated="2"><table-cell><p>6135550123</p></table-cell></table-row>
+			<table-row number-rows-repeated="3"><table-cell number-columns-repeated="2"/></table-row>
+			<table-row><table-cell/><table-cell><p>Sam</p></table-cell></table-row>
+		</table></spreadsheet></body></document-content>`,
+	}))
+
+	csv, _ := NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader("email address,name\n\nalex@example.com,Alex\n\n,Sam\n"))
+
+	cases := map[string]struct {
+		bulk Bulk
+		want string
+	}{
+		"xlsx": {xlsx, "row 5 has no email address"},
+		"ods":  {ods, "row 7 has no phone number"},
+		"csv":  {csv, "row 5 has no email address"},
+	}
+
+	for name, tc := range cases {
+		if _, err := c.SendBulk(tc.bulk); !errors.Is(err, ErrInvalidBulk) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected ErrInvalidBulk containing %q, got %v", name, tc.want, err)
+		}
+	}
+}
@@ -1,9 +1,8 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/google/go-querystring/query"
@@ -11,21 +10,22 @@ import (
 
 type StatusResponse struct {
 	// Valid Response
-	Id                string           `json:"id"`
-	Reference         string           `json:"reference"`
-	EmailAddress      string           `json:"email_address"`
-	PhoneNumber       string           `json:"phone_number"`
-	Type              string           `json:"type"`
-	Status            string           `json:"status"`
-	StatusDescription string           `json:"status_description"`
-	ProviderResponse  string           `json:"provider_response"`
-	Template          responseTemplate `json:"template"`
-	Body              string           `json:"body"`
-	Subject           string           `json:"subject"`
-	CreatedAt         time.Time        `json:"created_at"`
-	CreatedByName     string           `json:"created_by_name"`
-	SentAt            time.Time        `json:"sent_at"`
-	CompletedAt       time.Time        `json:"completed_at"`
+	Id                string             `json:"id"`
+	Reference         string             `json:"reference"`
+	EmailAddress      string             `json:"email_address"`
+	PhoneNumber       string             `json:"phone_number"`
+	Type              TemplateType       `json:"type"`
+	Status            NotificationStatus `json:"status"`
+	StatusDescription string             `json:"status_description"`
+	ProviderResponse  string             `json:"provider_response"`
+	Template          responseTemplate   `json:"template"`
+	JobId             string             `json:"job_id"`
+	Body              string             `json:"body"`
+	Subject           string             `json:"subject"`
+	CreatedAt         time.Time          `json:"created_at"`
+	CreatedByName     string             `json:"created_by_name"`
+	SentAt            time.Time          `json:"sent_at"`
+	CompletedAt       time.Time          `json:"completed_at"`
 
 	// Error Response
 	StatusCode int             `json:"status_code"`
@@ -48,54 +48,55 @@ type Link struct {
 }
 
 type StatusQueryOptions struct {
-	OlderThan    string `url:"older_than,omitempty"`
-	Reference    string `url:"reference,omitempty"`
-	Status       string `url:"status,omitempty"`
-	TemplateType string `url:"template_type,omitempty"`
+	OlderThan    string             `url:"older_than,omitempty"`
+	Reference    string             `url:"reference,omitempty"`
+	Status       NotificationStatus `url:"status,omitempty"`
+	TemplateType TemplateType       `url:"template_type,omitempty"`
+
+	// Optional, include notifications sent by bulk jobs
+	IncludeJobs bool `url:"include_jobs,omitempty"`
 }
 
-func doGetStatus[T StatusResponse | StatusResponses](c Client, url string, response T) (T, int, error) {
-	resp, err := c.DoGetRequest(url)
+func (c Client) GetStatus(options StatusQueryOptions) (StatusResponses, error) {
+	return c.GetStatusContext(context.Background(), options)
+}
 
-	if err != nil {
-		return response, 0, fmt.Errorf("error calling status endpoint: %s", err)
+func (c Client) GetStatusContext(ctx context.Context, options StatusQueryOptions) (StatusResponses, error) {
+	if options.Status != "" && !options.Status.IsValid() {
+		return StatusResponses{}, fmt.Errorf("invalid status %q", options.Status)
 	}
 
-	defer resp.Body.Close()
-
-	err = json.NewDecoder(resp.Body).Decode(&response)
-
-	if err != nil {
-		return response, 0, fmt.Errorf("error decoding status response: %s", err)
+	if options.TemplateType != "" && !options.TemplateType.IsValid() {
+		return StatusResponses{}, fmt.Errorf("invalid template type %q", options.TemplateType)
 	}
 
-	return response, resp.StatusCode, nil
-}
-
-func (c Client) GetStatus(options StatusQueryOptions) (StatusResponses, error) {
 	v, _ := query.Values(options)
 
-	response, statusCode, err := doGetStatus(c, "/v2/notifications?"+v.Encode(), StatusResponses{})
+	response, statusCode, err := doGet(ctx, c, "status", "/v2/notifications?"+v.Encode(), StatusResponses{})
 
-	if err != nil {
+	if err != nil && statusCode == 0 {
 		return StatusResponses{}, err
 	}
 
 	response.StatusCode = statusCode
 
-	return response, nil
+	return response, err
 }
 
 func (c Client) GetStatusById(id string) (StatusResponse, error) {
-	response, statusCode, err := doGetStatus(c, "/v2/notifications/"+id, StatusResponse{})
+	return c.GetStatusByIdContext(context.Background(), id)
+}
 
-	if err != nil {
+func (c Client) GetStatusByIdContext(ctx context.Context, id string) (StatusResponse, error) {
+	response, statusCode, err := doGet(ctx, c, "status", "/v2/notifications/"+id, StatusResponse{})
+
+	if err != nil && statusCode == 0 {
 		return StatusResponse{}, err
 	}
 
 	response.StatusCode = statusCode
 
-	return response, nil
+	return response, err
 }
 
 func (s *StatusResponses) HasNext() bool {
@@ -103,15 +104,23 @@ func (s *StatusResponses) HasNext() bool {
 }
 
 func (c Client) NextStatusPage(s StatusResponses) (StatusResponses, error) {
-	url := strings.Replace(s.Links.Next, c.Hostname, "", 1)
+	return c.NextStatusPageContext(context.Background(), s)
+}
 
-	response, statusCode, err := doGetStatus(c, url, StatusResponses{})
+func (c Client) NextStatusPageContext(ctx context.Context, s StatusResponses) (StatusResponses, error) {
+	url, err := c.endpointFromLink(s.Links.Next)
 
 	if err != nil {
+		return StatusResponses{}, fmt.Errorf("error resolving next page: %s", err)
+	}
+
+	response, statusCode, err := doGet(ctx, c, "status", url, StatusResponses{})
+
+	if err != nil && statusCode == 0 {
 		return StatusResponses{}, err
 	}
 
 	response.StatusCode = statusCode
 
-	return response, nil
+	return response, err
 }
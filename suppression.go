@@ -0,0 +1,162 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrSuppressedRecipient = errors.New("recipient is on the suppression list")
+
+type SuppressionReason string
+
+const (
+	SuppressionComplaint        SuppressionReason = "complaint"
+	SuppressionPermanentFailure SuppressionReason = "permanent-failure"
+)
+
+// SuppressionList holds email addresses that shouldn't be sent to again.
+// When set on Client, SendEmail refuses to send to them.
+type SuppressionList interface {
+	IsSuppressed(address string) (bool, error)
+	Suppress(address string, reason SuppressionReason) error
+	Unsuppress(address string) error
+}
+
+type Suppression struct {
+	Reason       SuppressionReason `json:"reason"`
+	SuppressedAt time.Time         `json:"suppressed_at"`
+}
+
+func normaliseAddress(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
+
+// MemorySuppressionList is a SuppressionList for a single process. The zero
+// value is ready to use.
+type MemorySuppressionList struct {
+	mu        sync.Mutex
+	addresses map[string]Suppression
+}
+
+func (l *MemorySuppressionList) IsSuppressed(address string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, ok := l.addresses[normaliseAddress(address)]
+
+	return ok, nil
+}
+
+func (l *MemorySuppressionList) Suppress(address string, reason SuppressionReason) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.addresses == nil {
+		l.addresses = map[string]Suppression{}
+	}
+
+	l.addresses[normaliseAddress(address)] = Suppression{Reason: reason, SuppressedAt: time.Now().UTC()}
+
+	return nil
+}
+
+func (l *MemorySuppressionList) Unsuppress(address string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.addresses, normaliseAddress(address))
+
+	return nil
+}
+
+// FileSuppressionList is a SuppressionList kept in a JSON file, mapping each
+// address to its Suppression. The file is read once and written on every
+// change. It isn't safe to share the file between processes.
+type FileSuppressionList struct {
+	Path string
+
+	mu        sync.Mutex
+	addresses map[string]Suppression
+}
+
+func (l *FileSuppressionList) load() error {
+	if l.addresses != nil {
+		return nil
+	}
+
+	addresses := map[string]Suppression{}
+
+	body, err := os.ReadFile(l.Path)
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error reading suppression list: %w", err)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(body, &addresses); err != nil {
+			return fmt.Errorf("error decoding suppression list: %w", err)
+		}
+	}
+
+	l.addresses = addresses
+
+	return nil
+}
+
+func (l *FileSuppressionList) save() error {
+	body, err := json.MarshalIndent(l.addresses, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(l.Path, body, 0o600); err != nil {
+		return fmt.Errorf("error writing suppression list: %w", err)
+	}
+
+	return nil
+}
+
+func (l *FileSuppressionList) IsSuppressed(address string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.load(); err != nil {
+		return false, err
+	}
+
+	_, ok := l.addresses[normaliseAddress(address)]
+
+	return ok, nil
+}
+
+func (l *FileSuppressionList) Suppress(address string, reason SuppressionReason) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.load(); err != nil {
+		return err
+	}
+
+	l.addresses[normaliseAddress(address)] = Suppression{Reason: reason, SuppressedAt: time.Now().UTC()}
+
+	return l.save()
+}
+
+func (l *FileSuppressionList) Unsuppress(address string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.load(); err != nil {
+		return err
+	}
+
+	delete(l.addresses, normaliseAddress(address))
+
+	return l.save()
+}
@@ -0,0 +1,62 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestSendEmailToSuppressedAddress(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request to be made")
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.Suppressions = &MemorySuppressionList{}
+
+	c.Suppressions.Suppress("Test@Test.com", SuppressionComplaint)
+
+	_, err := c.SendEmail(Email{
+		EmailAddress: " test@test.com",
+		TemplateId:   "00000000-0000-0000-0000-000000000000",
+	})
+
+	if !errors.Is(err, ErrSuppressedRecipient) {
+		t.Errorf("Expected ErrSuppressedRecipient, got %v", err)
+	}
+}
+
+func TestFileSuppressionList(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "suppressions.json")
+
+	l := &FileSuppressionList{Path: path}
+
+	if err := l.Suppress("test@test.com", SuppressionPermanentFailure); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	l.Suppress("test2@test.com", SuppressionComplaint)
+	l.Unsuppress("test2@test.com")
+
+	// A new list reads the same file
+	l = &FileSuppressionList{Path: path}
+
+	if suppressed, err := l.IsSuppressed("TEST@test.com"); err != nil || !suppressed {
+		t.Errorf("Expected test@test.com to be suppressed, got %v %v", suppressed, err)
+	}
+
+	if suppressed, _ := l.IsSuppressed("test2@test.com"); suppressed {
+		t.Errorf("Expected test2@test.com not to be suppressed")
+	}
+}
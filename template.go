@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type Template struct {
+	// Valid Response
+	Id                 string       `json:"id"`
+	Name               string       `json:"name"`
+	Type               TemplateType `json:"type"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
+	CreatedBy          string       `json:"created_by"`
+	Version            int          `json:"version"`
+	Body               string       `json:"body"`
+	Subject            string       `json:"subject"`
+	LetterContactBlock string       `json:"letter_contact_block"`
+	Postage            string       `json:"postage"`
+
+	// Error Response
+	StatusCode int             `json:"status_code"`
+	Errors     []ResponseError `json:"errors"`
+}
+
+type Templates struct {
+	// Valid Response
+	Templates []Template `json:"templates"`
+
+	// Error Response
+	StatusCode int             `json:"status_code"`
+	Errors     []ResponseError `json:"errors"`
+}
+
+type TemplatePreview struct {
+	// Valid Response
+	Id      string       `json:"id"`
+	Type    TemplateType `json:"type"`
+	Version int          `json:"version"`
+	Body    string       `json:"body"`
+	Subject string       `json:"subject"`
+	Html    string       `json:"html"`
+	Postage string       `json:"postage"`
+
+	// Error Response
+	StatusCode int             `json:"status_code"`
+	Errors     []ResponseError `json:"errors"`
+}
+
+func (c Client) GetTemplate(id string) (Template, error) {
+	return c.GetTemplateContext(context.Background(), id)
+}
+
+func (c Client) GetTemplateContext(ctx context.Context, id string) (Template, error) {
+	if c.Templates != nil {
+		if t, ok := c.Templates.get(id); ok {
+			return t, nil
+		}
+	}
+
+	t, err := c.getTemplate(ctx, "/v2/template/"+url.PathEscape(id))
+
+	if err == nil && c.Templates != nil && t.StatusCode >= 200 && t.StatusCode < 300 {
+		c.Templates.set(id, t)
+	}
+
+	return t, err
+}
+
+func (c Client) GetTemplateVersion(id string, version int) (Template, error) {
+	return c.GetTemplateVersionContext(context.Background(), id, version)
+}
+
+func (c Client) GetTemplateVersionContext(ctx context.Context, id string, version int) (Template, error) {
+	return c.getTemplate(ctx, fmt.Sprintf("/v2/template/%s/version/%d", url.PathEscape(id), version))
+}
+
+func (c Client) getTemplate(ctx context.Context, endpoint string) (Template, error) {
+	response, statusCode, err := doGet(ctx, c, "template", endpoint, Template{})
+
+	if err != nil && statusCode == 0 {
+		return Template{}, err
+	}
+
+	response.StatusCode = statusCode
+
+	return response, err
+}
+
+// ListTemplates returns the latest version of every template of type t, or
+// of every template when t is empty.
+func (c Client) ListTemplates(t TemplateType) (Templates, error) {
+	return c.ListTemplatesContext(context.Background(), t)
+}
+
+func (c Client) ListTemplatesContext(ctx context.Context, t TemplateType) (Templates, error) {
+	endpoint := "/v2/templates"
+
+	if t != "" {
+		endpoint += "?type=" + url.QueryEscape(string(t))
+	}
+
+	response, statusCode, err := doGet(ctx, c, "templates", endpoint, Templates{})
+
+	if err != nil && statusCode == 0 {
+		return Templates{}, err
+	}
+
+	response.StatusCode = statusCode
+
+	return response, err
+}
+
+// PreviewTemplate renders a template with personalisation on the Notify API.
+func (c Client) PreviewTemplate(id string, personalisation map[string]interface{}) (TemplatePreview, error) {
+	return c.PreviewTemplateContext(context.Background(), id, personalisation)
+}
+
+func (c Client) PreviewTemplateContext(ctx context.Context, id string, personalisation map[string]interface{}) (TemplatePreview, error) {
+	if personalisation == nil {
+		personalisation = map[string]interface{}{}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"personalisation": personalisation})
+
+	var response TemplatePreview
+
+	if err != nil {
+		return response, fmt.Errorf("error marshalling body: %s", err)
+	}
+
+	resp, err := c.DoPostRequestContext(ctx, "/v2/template/"+url.PathEscape(id)+"/preview", body)
+
+	if err != nil {
+		return response, fmt.Errorf("error calling template preview endpoint: %w", err)
+	}
+
+	raw, err := readResponse(resp, &response)
+
+	response.StatusCode = resp.StatusCode
+
+	if err != nil {
+		return response, fmt.Errorf("error decoding template preview response: %w", err)
+	}
+
+	return response, c.checkResponse(resp.StatusCode, raw)
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrTemplateVersionMismatch = errors.New("template version mismatch")
+
+// TemplateVersionError is returned when a pinned template has a different
+// version on Notify. It matches ErrTemplateVersionMismatch with errors.Is.
+type TemplateVersionError struct {
+	TemplateId string
+	Pinned     int
+	Live       int
+
+	// The change was only seen in Notify's response, so the notification was
+	// sent with the Live version and the response is returned with the error.
+	// Sending again would send a second notification.
+	Sent bool
+}
+
+func (e *TemplateVersionError) Error() string {
+	if e.Sent {
+		return fmt.Sprintf("%s: template %s is pinned to version %d but was sent with version %d", ErrTemplateVersionMismatch, e.TemplateId, e.Pinned, e.Live)
+	}
+
+	return fmt.Sprintf("%s: template %s is pinned to version %d but version %d is live", ErrTemplateVersionMismatch, e.TemplateId, e.Pinned, e.Live)
+}
+
+func (e *TemplateVersionError) Is(target error) bool {
+	return target == ErrTemplateVersionMismatch
+}
+
+// TemplateCache keeps templates fetched with GetTemplate for TTL. Templates
+// can be pinned to the version they were tested against, in which case
+// sends using them fail when a different version is live. It's safe for
+// concurrent use.
+type TemplateCache struct {
+	// Optional, defaults to 5 minutes
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]templateCacheEntry
+	pins    map[string]int
+}
+
+type templateCacheEntry struct {
+	template Template
+	expires  time.Time
+}
+
+func (tc *TemplateCache) get(id string) (Template, bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	entry, ok := tc.entries[id]
+
+	if !ok || time.Now().After(entry.expires) {
+		return Template{}, false
+	}
+
+	return entry.template, true
+}
+
+func (tc *TemplateCache) set(id string, t Template) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	ttl := tc.TTL
+
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+
+	if tc.entries == nil {
+		tc.entries = map[string]templateCacheEntry{}
+	}
+
+	tc.entries[id] = templateCacheEntry{template: t, expires: time.Now().Add(ttl)}
+}
+
+func (tc *TemplateCache) Invalidate(id string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	delete(tc.entries, id)
+}
+
+func (tc *TemplateCache) InvalidateAll() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.entries = nil
+}
+
+// Pin makes sends using template id fail unless version is live.
+func (tc *TemplateCache) Pin(id string, version int) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.pins == nil {
+		tc.pins = map[string]int{}
+	}
+
+	tc.pins[id] = version
+}
+
+func (tc *TemplateCache) Unpin(id string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	delete(tc.pins, id)
+}
+
+// PinnedVersion returns the version template id is pinned to.
+func (tc *TemplateCache) PinnedVersion(id string) (int, bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	version, ok := tc.pins[id]
+
+	return version, ok
+}
+
+// checkPinnedVersion is called before a send, and fails if the template is
+// pinned and the cached or fetched template has another version.
+func (c Client) checkPinnedVersion(ctx context.Context, id string) error {
+	if c.Templates == nil {
+		return nil
+	}
+
+	pinned, ok := c.Templates.PinnedVersion(id)
+
+	if !ok {
+		return nil
+	}
+
+	t, err := c.GetTemplateContext(ctx, id)
+
+	if err != nil {
+		return fmt.Errorf("error checking pinned template version: %w", err)
+	}
+
+	if t.StatusCode < 200 || t.StatusCode >= 300 {
+		return fmt.Errorf("error checking pinned template version: %w", &APIError{StatusCode: t.StatusCode, Errors: t.Errors})
+	}
+
+	if t.Version != pinned {
+		return &TemplateVersionError{TemplateId: id, Pinned: pinned, Live: t.Version}
+	}
+
+	return nil
+}
+
+// checkSentVersion is called after a successful send, with the template
+// version Notify used. The template may have changed since it was cached, in
+// which case the cached copy is dropped and the error has Sent set.
+func (c Client) checkSentVersion(id string, version int) error {
+	if c.Templates == nil || version == 0 {
+		return nil
+	}
+
+	pinned, ok := c.Templates.PinnedVersion(id)
+
+	if !ok || version == pinned {
+		return nil
+	}
+
+	c.Templates.Invalidate(id)
+
+	return &TemplateVersionError{TemplateId: id, Pinned: pinned, Live: version, Sent: true}
+}
@@ -0,0 +1,125 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestTemplateCache(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(templateBody))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.Templates = &TemplateCache{TTL: time.Hour}
+
+	for i := 0; i < 2; i++ {
+		got, err := c.GetTemplate("00000000-0000-0000-0000-000000000000")
+
+		if err != nil || got.Version != 3 {
+			t.Errorf("Expected version 3, got %d %v", got.Version, err)
+		}
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 request, got %d", calls.Load())
+	}
+
+	c.Templates.Invalidate("00000000-0000-0000-0000-000000000000")
+	c.GetTemplate("00000000-0000-0000-0000-000000000000")
+
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 requests after invalidating, got %d", calls.Load())
+	}
+
+	c.Templates.TTL = time.Nanosecond
+	c.Templates.InvalidateAll()
+	c.GetTemplate("00000000-0000-0000-0000-000000000000")
+	time.Sleep(time.Millisecond)
+	c.GetTemplate("00000000-0000-0000-0000-000000000000")
+
+	if calls.Load() != 4 {
+		t.Errorf("Expected 4 requests after expiry, got %d", calls.Load())
+	}
+}
+
+func TestTemplateCachePinnedVersion(t *testing.T) {
+	t.Parallel()
+
+	var sends atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(templateBody))
+			return
+		}
+
+		sends.Add(1)
+
+		// The template changed since it was cached
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "1", "template": {"id": "00000000-0000-0000-0000-000000000000", "version": 4}}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.Templates = &TemplateCache{}
+
+	e := Email{
+		EmailAddress: "test@test.com",
+		TemplateId:   "00000000-0000-0000-0000-000000000000",
+	}
+
+	c.Templates.Pin(e.TemplateId, 2)
+
+	_, err := c.SendEmail(e)
+
+	var versionErr *TemplateVersionError
+
+	if !errors.As(err, &versionErr) || versionErr.Pinned != 2 || versionErr.Live != 3 || versionErr.Sent {
+		t.Errorf("Expected version 2 to be pinned and version 3 live, got %v", err)
+	}
+
+	if sends.Load() != 0 {
+		t.Errorf("Expected no email to be sent, got %d", sends.Load())
+	}
+
+	c.Templates.Pin(e.TemplateId, 3)
+
+	resp, err := c.SendEmail(e)
+
+	if !errors.Is(err, ErrTemplateVersionMismatch) {
+		t.Errorf("Expected ErrTemplateVersionMismatch, got %v", err)
+	}
+
+	// The change was only seen in the response, once the email was sent
+	if !errors.As(err, &versionErr) || !versionErr.Sent || versionErr.Live != 4 {
+		t.Errorf("Expected the error to show the email was sent with version 4, got %v", err)
+	}
+
+	if resp.Id != "1" {
+		t.Errorf("Expected the response to be returned, got %+v", resp)
+	}
+
+	c.Templates.Unpin(e.TemplateId)
+
+	if _, err := c.SendEmail(e); err != nil {
+		t.Errorf("Expected no error once unpinned, got %s", err)
+	}
+}
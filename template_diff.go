@@ -0,0 +1,101 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffTemplates returns a line by line diff of the name, subject and body of
+// two templates, or an empty string when their content is the same. Removed
+// lines start with "-", added lines with "+" and unchanged lines with a space.
+func DiffTemplates(from, to Template) string {
+	var b strings.Builder
+
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"name", from.Name, to.Name},
+		{"subject", from.Subject, to.Subject},
+		{"body", from.Body, to.Body},
+		{"letter contact block", from.LetterContactBlock, to.LetterContactBlock},
+		{"postage", from.Postage, to.Postage},
+	}
+
+	for _, f := range fields {
+		if f.from == f.to {
+			continue
+		}
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s version %d\n+++ %s version %d\n", from.Id, from.Version, to.Id, to.Version)
+		}
+
+		fmt.Fprintf(&b, "@@ %s @@\n", f.name)
+
+		for _, line := range diffLines(splitLines(f.from), splitLines(f.to)) {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+
+	return b.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+// diffLines returns the lines of a and b prefixed with "-", "+" or a space,
+// using their longest common subsequence.
+func diffLines(a, b []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		lines = append(lines, "-"+a[i])
+	}
+
+	for ; j < len(b); j++ {
+		lines = append(lines, "+"+b[j])
+	}
+
+	return lines
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// templateFile is the layout of an exported template, leaving out the error
+// fields of Template so exports only change when the template does.
+type templateFile struct {
+	Id                 string       `json:"id"`
+	Name               string       `json:"name"`
+	Type               TemplateType `json:"type"`
+	Version            int          `json:"version"`
+	CreatedBy          string       `json:"created_by"`
+	CreatedAt          string       `json:"created_at"`
+	UpdatedAt          string       `json:"updated_at,omitempty"`
+	Subject            string       `json:"subject,omitempty"`
+	Body               string       `json:"body"`
+	LetterContactBlock string       `json:"letter_contact_block,omitempty"`
+	Postage            string       `json:"postage,omitempty"`
+}
+
+// ExportTemplates writes every version of every template to
+// dir/<template id>/v<version>.json. Versions already in dir aren't fetched
+// again, so exporting regularly only fetches the versions made since. It
+// returns the paths of the files that were created or changed.
+func (c Client) ExportTemplates(dir string) ([]string, error) {
+	return c.ExportTemplatesContext(context.Background(), dir)
+}
+
+func (c Client) ExportTemplatesContext(ctx context.Context, dir string) ([]string, error) {
+	list, err := c.ListTemplatesContext(ctx, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if list.StatusCode < 200 || list.StatusCode >= 300 {
+		return nil, &APIError{StatusCode: list.StatusCode, Errors: list.Errors}
+	}
+
+	var written []string
+
+	for _, t := range list.Templates {
+		paths, err := c.exportTemplate(ctx, dir, t)
+		written = append(written, paths...)
+
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// exportTemplate writes latest, the latest version of a template, after
+// fetching and writing the earlier versions missing from dir.
+func (c Client) exportTemplate(ctx context.Context, dir string, latest Template) ([]string, error) {
+	exported, err := ExportedTemplateVersions(dir, latest.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var written []string
+
+	for version := 1; version <= latest.Version; version++ {
+		t := latest
+
+		if version < latest.Version {
+			if slices.Contains(exported, version) {
+				continue
+			}
+
+			t, err = c.GetTemplateVersionContext(ctx, latest.Id, version)
+
+			if err != nil {
+				return written, fmt.Errorf("error fetching template %s version %d: %w", latest.Id, version, err)
+			}
+
+			if t.StatusCode < 200 || t.StatusCode >= 300 {
+				return written, fmt.Errorf("error fetching template %s version %d: %w", latest.Id, version, &APIError{StatusCode: t.StatusCode, Errors: t.Errors})
+			}
+		}
+
+		path, changed, err := writeTemplateFile(dir, t)
+
+		if err != nil {
+			return written, err
+		}
+
+		if changed {
+			written = append(written, path)
+		}
+	}
+
+	return written, nil
+}
+
+func writeTemplateFile(dir string, t Template) (string, bool, error) {
+	if t.Id == "" || strings.ContainsAny(t.Id, `/\.`) {
+		return "", false, fmt.Errorf("can't export template with id %q", t.Id)
+	}
+
+	f := templateFile{
+		Id:                 t.Id,
+		Name:               t.Name,
+		Type:               t.Type,
+		Version:            t.Version,
+		CreatedBy:          t.CreatedBy,
+		CreatedAt:          t.CreatedAt.Format("2006-01-02T15:04:05.000000Z07:00"),
+		Subject:            t.Subject,
+		Body:               t.Body,
+		LetterContactBlock: t.LetterContactBlock,
+		Postage:            t.Postage,
+	}
+
+	if !t.UpdatedAt.IsZero() {
+		f.UpdatedAt = t.UpdatedAt.Format("2006-01-02T15:04:05.000000Z07:00")
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+
+	if err != nil {
+		return "", false, err
+	}
+
+	data = append(data, '\n')
+	path := filepath.Join(dir, t.Id, fmt.Sprintf("v%d.json", t.Version))
+
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return path, false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", false, err
+	}
+
+	return path, true, os.WriteFile(path, data, 0o644)
+}
+
+// ReadExportedTemplate reads a template written by ExportTemplates. The
+// latest exported version is read when version is 0.
+func ReadExportedTemplate(dir string, id string, version int) (Template, error) {
+	if version == 0 {
+		versions, err := ExportedTemplateVersions(dir, id)
+
+		if err != nil {
+			return Template{}, err
+		}
+
+		if len(versions) == 0 {
+			return Template{}, fmt.Errorf("template %s hasn't been exported: %w", id, fs.ErrNotExist)
+		}
+
+		version = versions[len(versions)-1]
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, id, fmt.Sprintf("v%d.json", version)))
+
+	if err != nil {
+		return Template{}, err
+	}
+
+	var t Template
+
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Template{}, fmt.Errorf("error decoding template %s version %d: %w", id, version, err)
+	}
+
+	return t, nil
+}
+
+// ExportedTemplateVersions returns the exported versions of a template in
+// ascending order.
+func ExportedTemplateVersions(dir string, id string) ([]int, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, id))
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []int
+
+	for _, e := range entries {
+		name := e.Name()
+
+		if !strings.HasPrefix(name, "v") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		if v, err := strconv.Atoi(strings.TrimSuffix(name[1:], ".json")); err == nil {
+			versions = append(versions, v)
+		}
+	}
+
+	// ReadDir sorts by name, which puts v10 before v2
+	slices.Sort(versions)
+
+	return versions, nil
+}
@@ -0,0 +1,140 @@
+package client_test
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestExportTemplates(t *testing.T) {
+	t.Parallel()
+
+	var fetched []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/templates":
+			w.Write([]byte(`{"templates": [` + templateBody + `]}`))
+		case "/v2/template/00000000-0000-0000-0000-000000000000/version/1":
+			fetched = append(fetched, r.URL.Path)
+			w.Write([]byte(strings.Replace(strings.Replace(templateBody, `"version": 3`, `"version": 1`, 1), "Hello", "Hi", 1)))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	dir := t.TempDir()
+
+	// An earlier version is kept
+	os.MkdirAll(filepath.Join(dir, "00000000-0000-0000-0000-000000000000"), 0o755)
+	os.WriteFile(filepath.Join(dir, "00000000-0000-0000-0000-000000000000", "v10.json"), []byte(`{"version": 10}`), 0o644)
+	os.WriteFile(filepath.Join(dir, "00000000-0000-0000-0000-000000000000", "v2.json"), []byte(`{"version": 2, "body": "Hi ((name))"}`), 0o644)
+
+	written, err := c.ExportTemplates(dir)
+
+	if err != nil {
+		t.Fatalf("ExportTemplates returned an error: %s", err)
+	}
+
+	// Version 2 is already exported, so only version 1 is fetched
+	want := filepath.Join(dir, "00000000-0000-0000-0000-000000000000", "v3.json")
+	first := filepath.Join(dir, "00000000-0000-0000-0000-000000000000", "v1.json")
+
+	if !reflect.DeepEqual(written, []string{first, want}) {
+		t.Errorf("Expected %v and %v to be written, got %v", first, want, written)
+	}
+
+	data, _ := os.ReadFile(want)
+
+	if strings.Contains(string(data), "status_code") {
+		t.Errorf("Expected error fields to be left out of the export, got %s", data)
+	}
+
+	// Exporting again doesn't change anything
+	if written, _ := c.ExportTemplates(dir); len(written) != 0 {
+		t.Errorf("Expected nothing to be written, got %v", written)
+	}
+
+	if len(fetched) != 1 {
+		t.Errorf("Expected version 1 to be fetched once, got %v", fetched)
+	}
+
+	versions, _ := ExportedTemplateVersions(dir, "00000000-0000-0000-0000-000000000000")
+
+	if !reflect.DeepEqual(versions, []int{1, 2, 3, 10}) {
+		t.Errorf("Expected versions [1 2 3 10], got %v", versions)
+	}
+
+	if first, _ := ReadExportedTemplate(dir, "00000000-0000-0000-0000-000000000000", 1); first.Body != "Hi ((name))" {
+		t.Errorf("Expected version 1 to be exported, got %+v", first)
+	}
+
+	exported, err := ReadExportedTemplate(dir, "00000000-0000-0000-0000-000000000000", 3)
+
+	if err != nil {
+		t.Fatalf("ReadExportedTemplate returned an error: %s", err)
+	}
+
+	if exported.Body != "Hello ((name))" || exported.Version != 3 || exported.CreatedAt.IsZero() {
+		t.Errorf("Unexpected exported template %+v", exported)
+	}
+
+	if DiffTemplates(exported, exported) != "" {
+		t.Errorf("Expected no difference between identical templates")
+	}
+
+	if _, err := ReadExportedTemplate(dir, "11111111-1111-1111-1111-111111111111", 0); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected an error for a template that wasn't exported, got %v", err)
+	}
+}
+
+func TestExportTemplatesError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors": [{"error": "AuthError", "message": "Invalid token"}], "status_code": 403}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	if _, err := c.ExportTemplates(t.TempDir()); err == nil || !strings.Contains(err.Error(), "Invalid token") {
+		t.Errorf("Expected an API error, got %v", err)
+	}
+}
+
+func TestDiffTemplates(t *testing.T) {
+	t.Parallel()
+
+	from := Template{Id: "1", Version: 1, Name: "Welcome", Subject: "Hi", Body: "Hello ((name))\n\nThanks\nThe team"}
+	to := Template{Id: "1", Version: 2, Name: "Welcome", Subject: "Hi", Body: "Hello ((name))\n\nMerci\nThe team"}
+
+	want := "--- 1 version 1\n+++ 1 version 2\n@@ body @@\n Hello ((name))\n \n-Thanks\n+Merci\n The team\n"
+
+	if got := DiffTemplates(from, to); got != want {
+		t.Errorf("Expected diff\n%s\ngot\n%s", want, got)
+	}
+
+	to.Subject = ""
+
+	if got := DiffTemplates(from, to); !strings.Contains(got, "@@ subject @@\n-Hi\n") {
+		t.Errorf("Expected the removed subject in the diff, got\n%s", got)
+	}
+}
@@ -0,0 +1,166 @@
+package client_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+const templateBody = `{
+	"id": "00000000-0000-0000-0000-000000000000",
+	"name": "Welcome",
+	"type": "email",
+	"created_at": "2024-01-01T12:00:00.000000Z",
+	"updated_at": null,
+	"created_by": "test@test.com",
+	"version": 3,
+	"body": "Hello ((name))",
+	"subject": "Welcome",
+	"letter_contact_block": null,
+	"postage": null
+}`
+
+func TestGetTemplate(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the request URL
+		if r.URL.Path != "/v2/template/00000000-0000-0000-0000-000000000000" && r.URL.Path != "/v2/template/00000000-0000-0000-0000-000000000000/version/3" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+
+		// Verify the request method
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+
+		w.Write([]byte(templateBody))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	want := Template{
+		Id:         "00000000-0000-0000-0000-000000000000",
+		Name:       "Welcome",
+		Type:       TemplateTypeEmail,
+		CreatedAt:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		CreatedBy:  "test@test.com",
+		Version:    3,
+		Body:       "Hello ((name))",
+		Subject:    "Welcome",
+		StatusCode: 200,
+	}
+
+	got, err := c.GetTemplate("00000000-0000-0000-0000-000000000000")
+
+	if err != nil {
+		t.Errorf("Error calling GetTemplate(): %s", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTemplate() = %+v, want %+v", got, want)
+	}
+
+	got, err = c.GetTemplateVersion("00000000-0000-0000-0000-000000000000", 3)
+
+	if err != nil {
+		t.Errorf("Error calling GetTemplateVersion(): %s", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTemplateVersion() = %+v, want %+v", got, want)
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the request URL
+		if r.URL.Path != "/v2/templates" {
+			t.Errorf("Expected request to /v2/templates, got %s", r.URL.Path)
+		}
+
+		if r.URL.RawQuery != "type=sms" {
+			t.Errorf("Expected query string to be type=sms, got %s", r.URL.RawQuery)
+		}
+
+		w.Write([]byte(`{"templates": [` + templateBody + `]}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	got, err := c.ListTemplates(TemplateTypeSms)
+
+	if err != nil {
+		t.Errorf("Error calling ListTemplates(): %s", err)
+	}
+
+	if got.StatusCode != 200 || len(got.Templates) != 1 || got.Templates[0].Version != 3 {
+		t.Errorf("ListTemplates() = %+v", got)
+	}
+}
+
+func TestPreviewTemplate(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the request URL
+		if r.URL.Path != "/v2/template/00000000-0000-0000-0000-000000000000/preview" {
+			t.Errorf("Expected request to /v2/template/00000000-0000-0000-0000-000000000000/preview, got %s", r.URL.Path)
+		}
+
+		// Verify the request method
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+
+		var got map[string]map[string]string
+		json.Unmarshal(body, &got)
+
+		if got["personalisation"]["name"] != "Alex" {
+			t.Errorf("Expected personalisation to be sent, got %s", body)
+		}
+
+		w.Write([]byte(`{"id": "00000000-0000-0000-0000-000000000000", "type": "email", "version": 3, "body": "Hello Alex", "subject": "Welcome", "html": "<p>Hello Alex</p>"}`))
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	got, err := c.PreviewTemplate("00000000-0000-0000-0000-000000000000", map[string]interface{}{"name": "Alex"})
+
+	if err != nil {
+		t.Errorf("Error calling PreviewTemplate(): %s", err)
+	}
+
+	want := TemplatePreview{
+		Id:         "00000000-0000-0000-0000-000000000000",
+		Type:       TemplateTypeEmail,
+		Version:    3,
+		Body:       "Hello Alex",
+		Subject:    "Welcome",
+		Html:       "<p>Hello Alex</p>",
+		StatusCode: 200,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PreviewTemplate() = %+v, want %+v", got, want)
+	}
+}
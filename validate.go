@@ -0,0 +1,396 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MaxBulkRows is the most rows, after the header, Notify accepts in a bulk
+// send.
+const MaxBulkRows = 50000
+
+// RowError is a problem with a cell, a row or the header of a bulk send.
+type RowError struct {
+	// Row number counting the header as row 1, as shown by a spreadsheet
+	// opening the file. Blank rows are numbered too. Rows of Csv are numbered
+	// by the line they start on.
+	Row int
+
+	// Empty when the problem is with the whole row
+	Column string
+
+	Message string
+}
+
+func (e RowError) String() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: %s", e.Row, e.Message)
+	}
+
+	return fmt.Sprintf("row %d, %s: %s", e.Row, e.Column, e.Message)
+}
+
+type BulkReport struct {
+	// Template type the rows were checked for, empty when it couldn't be
+	// detected
+	Type TemplateType
+
+	// Rows read after the header, leaving out blank rows
+	Rows int
+
+	// Problems in row order
+	Errors []RowError
+}
+
+// Err returns an error matching ErrInvalidBulk listing the first problems, or
+// nil.
+func (r BulkReport) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+
+	const shown = 5
+
+	var problems []string
+
+	for _, e := range r.Errors[:min(len(r.Errors), shown)] {
+		problems = append(problems, e.String())
+	}
+
+	if len(r.Errors) > shown {
+		problems = append(problems, fmt.Sprintf("and %d more", len(r.Errors)-shown))
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidBulk, strings.Join(problems, "; "))
+}
+
+// String returns every problem, one per line.
+func (r BulkReport) String() string {
+	var b strings.Builder
+
+	for _, e := range r.Errors {
+		b.WriteString(e.String())
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// BulkValidator checks the rows of a bulk send against the rules Notify
+// applies once it has been uploaded.
+type BulkValidator struct {
+	// Optional, columns and cells for the placeholders of the template are
+	// checked, and its type is used instead of detecting it
+	Template *Template
+
+	// Optional, defaults to MaxBulkRows
+	MaxRows int
+
+	// Optional, accept phone numbers outside North America
+	International bool
+
+	// Optional, rows sent to the same recipient aren't reported
+	AllowDuplicates bool
+}
+
+// Validate returns a report of every problem with the Rows or Csv of b. Source
+// isn't read, as that would leave nothing to send, but can be checked with
+// ValidateRows. An error is only returned when b.Csv can't be read.
+func (v BulkValidator) Validate(b Bulk) (BulkReport, error) {
+	table, numbers, err := b.table()
+
+	if err != nil {
+		return BulkReport{}, fmt.Errorf("error reading csv: %s", err)
+	}
+
+	i := 0
+
+	return v.validate(b.Type, func() ([]string, int, error) {
+		if i == len(table) {
+			return nil, 0, io.EOF
+		}
+
+		i++
+
+		return table[i-1], numbers[i-1], nil
+	})
+}
+
+// ValidateRows returns a report of every problem with the rows read from r,
+// the first being the header, such as the Source of a bulk send from a file.
+// The template type is taken from Template or detected from the header. An
+// error is only returned when r can't be read.
+func (v BulkValidator) ValidateRows(r RowReader) (BulkReport, error) {
+	number := 0
+	header := true
+
+	return v.validate("", func() ([]string, int, error) {
+		row, err := r.Read()
+
+		if err != nil {
+			return nil, number, err
+		}
+
+		number = rowNumber(r, row, number+1)
+
+		// Headers are normalised as they are when sending from a file
+		if header {
+			row, header = normaliseHeader(row), false
+		}
+
+		return row, number, nil
+	})
+}
+
+// validate checks the rows returned by next, the first being the header,
+// until it returns io.EOF.
+func (v BulkValidator) validate(t TemplateType, next func() ([]string, int, error)) (BulkReport, error) {
+	var report BulkReport
+
+	header, _, err := next()
+
+	if err == io.EOF {
+		report.Errors = append(report.Errors, RowError{Row: 1, Message: "no header"})
+		return report, nil
+	}
+
+	if err != nil {
+		return report, fmt.Errorf("error reading header: %w", err)
+	}
+
+	columns := map[string]int{}
+
+	for i, name := range header {
+		key := normaliseKey(name)
+
+		if _, ok := columns[key]; ok {
+			report.Errors = append(report.Errors, RowError{Row: 1, Column: name, Message: "appears more than once"})
+			continue
+		}
+
+		columns[key] = i
+	}
+
+	if v.Template != nil {
+		t = v.Template.Type
+	}
+
+	if t == "" {
+		t, err = detectTemplateType(columns)
+
+		if err != nil {
+			report.Errors = append(report.Errors, RowError{Row: 1, Message: strings.TrimPrefix(err.Error(), ErrInvalidBulk.Error()+": ")})
+			return report, nil
+		}
+	}
+
+	report.Type = t
+
+	// Cells that must not be empty, by column index
+	required := map[int]string{}
+
+	for _, name := range recipientColumns[t] {
+		if i, ok := columns[normaliseKey(name)]; ok {
+			required[i] = name
+		} else {
+			report.Errors = append(report.Errors, RowError{Row: 1, Message: fmt.Sprintf("missing %s column", name)})
+		}
+	}
+
+	// Letters need a postcode in one of several columns
+	var postcode []int
+
+	if t == TemplateTypeLetter {
+		for _, name := range postcodeColumns {
+			if i, ok := columns[normaliseKey(name)]; ok {
+				postcode = append(postcode, i)
+			}
+		}
+
+		if len(postcode) == 0 {
+			report.Errors = append(report.Errors, RowError{Row: 1, Message: "missing postcode column"})
+		}
+	}
+
+	if v.Template != nil {
+		texts := []string{v.Template.Body}
+
+		if t == TemplateTypeEmail {
+			texts = append(texts, v.Template.Subject)
+		}
+
+		for _, field := range RequiredFields(texts...) {
+			if i, ok := columns[normaliseKey(field)]; ok {
+				required[i] = field
+			} else {
+				report.Errors = append(report.Errors, RowError{Row: 1, Message: fmt.Sprintf("missing column for ((%s))", field)})
+			}
+		}
+	}
+
+	maxRows := v.MaxRows
+
+	if maxRows <= 0 {
+		maxRows = MaxBulkRows
+	}
+
+	recipient, hasRecipient := columns[normaliseKey(recipientColumns[t][0])]
+	seen := map[string]int{}
+	indexes := slices.Sorted(maps.Keys(required))
+
+	for {
+		row, number, err := next()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return report, fmt.Errorf("error reading row after %d: %w", number, err)
+		}
+
+		// Notify skips blank rows, so they don't count against the limit
+		if isBlankRow(row) {
+			continue
+		}
+
+		report.Rows++
+
+		if report.Rows > maxRows {
+			report.Errors = append(report.Errors, RowError{Row: number, Message: fmt.Sprintf("more than %d rows", maxRows)})
+			break
+		}
+
+		for _, i := range indexes {
+			if i >= len(row) || strings.TrimSpace(row[i]) == "" {
+				report.Errors = append(report.Errors, RowError{Row: number, Column: required[i], Message: "missing"})
+			}
+		}
+
+		if len(postcode) > 0 && !slices.ContainsFunc(postcode, func(i int) bool {
+			return i < len(row) && strings.TrimSpace(row[i]) != ""
+		}) {
+			report.Errors = append(report.Errors, RowError{Row: number, Column: "postcode", Message: "missing"})
+		}
+
+		if !hasRecipient || recipient >= len(row) || strings.TrimSpace(row[recipient]) == "" {
+			continue
+		}
+
+		key, ok := v.recipientKey(t, row[recipient])
+
+		if !ok {
+			report.Errors = append(report.Errors, RowError{Row: number, Column: recipientColumns[t][0], Message: fmt.Sprintf("not a valid %s", recipientColumns[t][0])})
+			continue
+		}
+
+		if first, ok := seen[key]; ok && !v.AllowDuplicates {
+			report.Errors = append(report.Errors, RowError{Row: number, Column: recipientColumns[t][0], Message: fmt.Sprintf("same recipient as row %d", first)})
+		} else if !ok {
+			seen[key] = number
+		}
+	}
+
+	if report.Rows == 0 {
+		report.Errors = append(report.Errors, RowError{Row: 1, Message: "no rows after the header"})
+	}
+
+	return report, nil
+}
+
+// recipientKey checks a recipient and returns it in a form that is the same
+// for every way of writing it.
+func (v BulkValidator) recipientKey(t TemplateType, s string) (string, bool) {
+	s = strings.TrimSpace(s)
+
+	switch t {
+	case TemplateTypeEmail:
+		return strings.ToLower(s), isValidEmailAddress(s)
+	case TemplateTypeSms:
+		return normalisePhoneNumber(s, v.International)
+	default:
+		return strings.ToLower(strings.Join(strings.Fields(s), " ")), true
+	}
+}
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+var (
+	emailPattern        = regexp.MustCompile("^[a-zA-ZÀ-ÿ0-9.!#$%&'*+/=?^_`{|}~\\-]+@([^.@][^@\\s]+)$")
+	hostnamePartPattern = regexp.MustCompile(`^(?i)(xn|[\p{L}0-9]+)(-?-[\p{L}0-9]+)*$`)
+	tldPattern          = regexp.MustCompile(`^(?i)(\p{L}{2,63}|xn--([a-z0-9]+-)*[a-z0-9]+)$`)
+)
+
+// isValidEmailAddress follows the checks of Notify, which are looser than
+// RFC 5322 for the local part and stricter for the domain.
+func isValidEmailAddress(s string) bool {
+	if len(s) > 320 || strings.Contains(s, "..") {
+		return false
+	}
+
+	match := emailPattern.FindStringSubmatch(s)
+
+	if match == nil {
+		return false
+	}
+
+	hostname := match[1]
+	parts := strings.Split(hostname, ".")
+
+	if len(hostname) > 253 || len(parts) < 2 {
+		return false
+	}
+
+	for _, part := range parts {
+		if part == "" || len(part) > 63 || !hostnamePartPattern.MatchString(part) {
+			return false
+		}
+	}
+
+	return tldPattern.MatchString(parts[len(parts)-1])
+}
+
+var phoneReplacer = strings.NewReplacer(" ", "", "\u00a0", "", "-", "", "(", "", ")", "", ".", "", "/", "")
+
+// normalisePhoneNumber returns a phone number as digits with its country
+// code. Numbers without a country code are North American.
+func normalisePhoneNumber(s string, international bool) (string, bool) {
+	digits := phoneReplacer.Replace(s)
+	plus := strings.HasPrefix(digits, "+")
+	digits = strings.TrimPrefix(digits, "+")
+
+	if !plus && strings.HasPrefix(digits, "00") {
+		digits, plus = digits[2:], true
+	}
+
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return "", false
+	}
+
+	if len(digits) == 10 && !plus {
+		digits = "1" + digits
+	}
+
+	if len(digits) == 11 && digits[0] == '1' {
+		// Area codes and exchanges don't start with 0 or 1
+		return digits, digits[1] >= '2' && digits[4] >= '2'
+	}
+
+	if !international || !plus {
+		return "", false
+	}
+
+	return digits, len(digits) >= 8 && len(digits) <= 15 && digits[0] != '0'
+}
@@ -0,0 +1,218 @@
+package client_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestBulkValidator(t *testing.T) {
+	t.Parallel()
+
+	template := &Template{
+		Type:    TemplateTypeEmail,
+		Subject: "Hello ((Name))",
+		Body:    "Your code is ((code)). ((urgent??Act now.))",
+	}
+
+	b := Bulk{
+		Csv: "Email_Address,name,code,urgent\n" +
+			"alex@example.com,Alex,123,yes\n" +
+			"not-an-email,Sam,456,no\n" +
+			",Kim,789,no\n" +
+			"\n" +
+			"ALEX@example.com,Alex,123,\n" +
+			"jo@example.com,Jo\n",
+	}
+
+	report, err := BulkValidator{Template: template}.Validate(b)
+
+	if err != nil {
+		t.Fatalf("Validate returned an error: %s", err)
+	}
+
+	want := []RowError{
+		{Row: 3, Column: "email address", Message: "not a valid email address"},
+		{Row: 4, Column: "email address", Message: "missing"},
+		{Row: 6, Column: "urgent", Message: "missing"},
+		{Row: 6, Column: "email address", Message: "same recipient as row 2"},
+		{Row: 7, Column: "code", Message: "missing"},
+		{Row: 7, Column: "urgent", Message: "missing"},
+	}
+
+	if !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("Validate() errors = %v, want %v", report.Errors, want)
+	}
+
+	if report.Type != TemplateTypeEmail || report.Rows != 5 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	if err := report.Err(); !errors.Is(err, ErrInvalidBulk) || !strings.Contains(err.Error(), "and 1 more") {
+		t.Errorf("Expected an error matching ErrInvalidBulk, got %v", err)
+	}
+
+	if !strings.HasPrefix(report.String(), "row 3, email address: not a valid email address\n") {
+		t.Errorf("Unexpected report string %q", report.String())
+	}
+
+	report, _ = BulkValidator{Template: template, AllowDuplicates: true}.Validate(b)
+
+	if len(report.Errors) != 5 {
+		t.Errorf("Expected 5 errors when duplicates are allowed, got %v", report.Errors)
+	}
+}
+
+func TestBulkValidatorHeader(t *testing.T) {
+	t.Parallel()
+
+	template := &Template{Type: TemplateTypeSms, Body: "((name))"}
+
+	report, _ := BulkValidator{Template: template}.Validate(Bulk{Rows: [][]string{{"email address", "Email-Address"}}})
+
+	want := []RowError{
+		{Row: 1, Column: "Email-Address", Message: "appears more than once"},
+		{Row: 1, Message: "missing phone number column"},
+		{Row: 1, Message: "missing column for ((name))"},
+		{Row: 1, Message: "no rows after the header"},
+	}
+
+	if !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("Validate() errors = %v, want %v", report.Errors, want)
+	}
+
+	report, _ = BulkValidator{}.Validate(Bulk{Rows: [][]string{{"name"}, {"Alex"}}})
+
+	if len(report.Errors) != 1 || !strings.Contains(report.Errors[0].Message, "no email address, phone number or address line 1 column") {
+		t.Errorf("Expected an undetectable type error, got %v", report.Errors)
+	}
+}
+
+func TestBulkValidatorLetterPostcode(t *testing.T) {
+	t.Parallel()
+
+	report, _ := BulkValidator{}.Validate(Bulk{Rows: [][]string{{"address line 1", "address line 2"}, {"Alex", "1 Main St"}}})
+
+	if want := []RowError{{Row: 1, Message: "missing postcode column"}}; !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("Validate() errors = %v, want %v", report.Errors, want)
+	}
+
+	rows := [][]string{
+		{"address line 1", "address line 2", "address line 3", "postcode"},
+		{"Alex", "1 Main St", "Ottawa ON  K1A 0B1", ""},
+		{"Sam", "2 Main St", "", ""},
+	}
+
+	report, _ = BulkValidator{}.Validate(Bulk{Rows: rows})
+
+	if want := []RowError{{Row: 3, Column: "postcode", Message: "missing"}}; !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("Validate() errors = %v, want %v", report.Errors, want)
+	}
+}
+
+func TestBulkValidatorRowLimit(t *testing.T) {
+	t.Parallel()
+
+	rows := [][]string{{"phone number"}, {"613 555 0123"}, {""}, {"+1 613 555 0125"}, {"613 555 0126"}}
+
+	report, _ := BulkValidator{MaxRows: 2}.Validate(Bulk{Rows: rows})
+
+	// The blank row is numbered but doesn't count against the limit
+	want := []RowError{{Row: 5, Message: "more than 2 rows"}}
+
+	if !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("Validate() errors = %v, want %v", report.Errors, want)
+	}
+
+	report, _ = BulkValidator{MaxRows: 2}.Validate(Bulk{Rows: rows[:4]})
+
+	if len(report.Errors) != 0 || report.Rows != 2 {
+		t.Errorf("Expected 2 rows and no errors, got %+v", report)
+	}
+}
+
+func TestBulkValidatorValidateRows(t *testing.T) {
+	t.Parallel()
+
+	b, _ := NewBulkFromCSV("Test", "00000000-0000-0000-0000-000000000000", strings.NewReader(" Phone_Number ,name,\n613 555 0123,Alex,\n\n613-555-0123,Sam,\n,Kim,\n"))
+
+	report, err := BulkValidator{}.ValidateRows(b.Source)
+
+	if err != nil {
+		t.Fatalf("ValidateRows returned an error: %s", err)
+	}
+
+	want := []RowError{
+		{Row: 4, Column: "phone number", Message: "same recipient as row 2"},
+		{Row: 5, Column: "phone number", Message: "missing"},
+	}
+
+	if !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("ValidateRows() errors = %v, want %v", report.Errors, want)
+	}
+
+	if report.Type != TemplateTypeSms || report.Rows != 3 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
+
+func TestBulkValidatorPhoneNumbers(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		number        string
+		international bool
+		valid         bool
+	}{
+		{"613-555-0123", false, true},
+		{"1 (613) 555-0123", false, true},
+		{"+16135550123", false, true},
+		{"123-555-0123", false, false},
+		{"613-055-0123", false, false},
+		{"555-0123", false, false},
+		{"+44 7700 900123", false, false},
+		{"+44 7700 900123", true, true},
+		{"0044 7700 900123", true, true},
+		{"07700 900123", true, false},
+		{"613-555-O123", false, false},
+	}
+
+	for _, tc := range cases {
+		report, _ := BulkValidator{International: tc.international}.Validate(Bulk{Rows: [][]string{{"phone number"}, {tc.number}}})
+
+		if valid := len(report.Errors) == 0; valid != tc.valid {
+			t.Errorf("%q (international %t): expected valid %t, got %v", tc.number, tc.international, tc.valid, report.Errors)
+		}
+	}
+}
+
+func TestBulkValidatorEmailAddresses(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"test@example.com":          true,
+		"first.last+tag@example.ca": true,
+		"élise@courriel.gc.ca":      true,
+		"test@münchen.de":           true,
+		"test@example":              false,
+		"test@example.c":            false,
+		"test..dots@example.com":    false,
+		"test@-example.com":         false,
+		"test@exa_mple.com":         false,
+		"test example@example.com":  false,
+		"test@example.com.":         false,
+		"@example.com":              false,
+		"test@xn--mnchen-3ya.de":    true,
+	}
+
+	for address, valid := range cases {
+		report, _ := BulkValidator{}.Validate(Bulk{Rows: [][]string{{"email address"}, {address}}})
+
+		if got := len(report.Errors) == 0; got != valid {
+			t.Errorf("%q: expected valid %t, got %v", address, valid, report.Errors)
+		}
+	}
+}
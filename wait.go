@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+type WaitOptions struct {
+	// Optional, delay before the second poll, defaults to 1s. It doubles
+	// after every poll up to MaxInterval.
+	Interval time.Duration
+
+	// Optional, defaults to 30s
+	MaxInterval time.Duration
+
+	// Optional, stop when this returns true instead of at a terminal status
+	Until func(s StatusResponse) bool
+}
+
+// WaitForStatus polls a notification until it reaches a terminal status, such
+// as delivered or permanent-failure, or until opts.Until is met. It returns
+// each status observed along the way, the last one being the most recent,
+// including when ctx ends first.
+//
+// International SMS can stay sent, as not every carrier sends delivery
+// receipts, so waiting for them should have a deadline.
+//
+// A notification that isn't found yet is polled again, including in Strict
+// mode. Any other non-2xx response ends the wait with an *APIError.
+func (c Client) WaitForStatus(ctx context.Context, id string, opts WaitOptions) ([]StatusResponse, error) {
+	interval := opts.Interval
+
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	maxInterval := opts.MaxInterval
+
+	if maxInterval <= 0 {
+		maxInterval = 30 * time.Second
+	}
+
+	done := opts.Until
+
+	if done == nil {
+		done = func(s StatusResponse) bool {
+			return s.Status.IsTerminal()
+		}
+	}
+
+	var timeline []StatusResponse
+
+	for {
+		status, err := c.GetStatusByIdContext(ctx, id)
+
+		// In Strict mode a notification that isn't found yet is an error
+		var apiErr *APIError
+
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			status.StatusCode, err = http.StatusNotFound, nil
+		}
+
+		if err != nil {
+			return timeline, err
+		}
+
+		switch {
+		case status.StatusCode == http.StatusNotFound:
+		case status.StatusCode < 200 || status.StatusCode >= 300:
+			return timeline, &APIError{StatusCode: status.StatusCode, Errors: status.Errors}
+		default:
+			if len(timeline) == 0 || timeline[len(timeline)-1].Status != status.Status {
+				timeline = append(timeline, status)
+			}
+
+			if done(status) {
+				return timeline, nil
+			}
+		}
+
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return timeline, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = min(interval*2, maxInterval)
+	}
+}
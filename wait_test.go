@@ -0,0 +1,153 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/cds-snc/notification-go-client"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	t.Parallel()
+
+	statuses := []NotificationStatus{"", "created", "sending", "sent", "delivered"}
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the request URL
+		if r.URL.Path != "/v2/notifications/00000000-0000-0000-0000-000000000000" {
+			t.Errorf("Expected request to /v2/notifications/00000000-0000-0000-0000-000000000000, got %s", r.URL.Path)
+		}
+
+		i := calls.Add(1) - 1
+
+		// Not found until the notification is created
+		if statuses[i] == "" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status_code": 404, "errors": [{"error": "NoResultFound", "message": "No result found"}]}`))
+			return
+		}
+
+		json.NewEncoder(w).Encode(StatusResponse{
+			Id:     "00000000-0000-0000-0000-000000000000",
+			Status: statuses[i],
+		})
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	timeline, err := c.WaitForStatus(context.Background(), "00000000-0000-0000-0000-000000000000", WaitOptions{
+		Interval:    time.Millisecond,
+		MaxInterval: 2 * time.Millisecond,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	var got []NotificationStatus
+
+	for _, s := range timeline {
+		got = append(got, s.Status)
+	}
+
+	// Sent isn't terminal, delivery receipts can still arrive
+	if len(got) != 4 || got[0] != "created" || got[1] != "sending" || got[2] != "sent" || got[3] != "delivered" {
+		t.Errorf("Expected timeline created, sending, sent, delivered, got %v", got)
+	}
+
+	if calls.Load() != 5 {
+		t.Errorf("Expected 5 requests, got %d", calls.Load())
+	}
+}
+
+func TestWaitForStatusStrictNotFound(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status_code": 404, "errors": [{"error": "NoResultFound", "message": "No result found"}]}`))
+			return
+		}
+
+		json.NewEncoder(w).Encode(StatusResponse{Status: "delivered"})
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+	c.Strict = true
+
+	timeline, err := c.WaitForStatus(context.Background(), "00000000-0000-0000-0000-000000000000", WaitOptions{Interval: time.Millisecond})
+
+	if err != nil {
+		t.Fatalf("Expected not found to be polled again, got %s", err)
+	}
+
+	if len(timeline) != 1 || timeline[0].Status != "delivered" || calls.Load() != 3 {
+		t.Errorf("Expected delivered after 3 requests, got %v after %d", timeline, calls.Load())
+	}
+}
+
+func TestWaitForStatusUntil(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(StatusResponse{Status: "sending"})
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	timeline, err := c.WaitForStatus(context.Background(), "00000000-0000-0000-0000-000000000000", WaitOptions{
+		Until: func(s StatusResponse) bool {
+			return s.Status == "sending"
+		},
+	})
+
+	if err != nil || len(timeline) != 1 {
+		t.Errorf("Expected to stop at sending, got %v %v", timeline, err)
+	}
+}
+
+func TestWaitForStatusContextDone(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(StatusResponse{Status: "sending"})
+	}))
+
+	defer server.Close()
+
+	c, _ := NewClient("test")
+	c.Hostname = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	timeline, err := c.WaitForStatus(ctx, "00000000-0000-0000-0000-000000000000", WaitOptions{Interval: time.Millisecond})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if len(timeline) != 1 || timeline[0].Status != "sending" {
+		t.Errorf("Expected timeline to contain sending, got %v", timeline)
+	}
+}